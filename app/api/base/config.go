@@ -0,0 +1,18 @@
+package base
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+)
+
+// effectiveConfig will return the running configuration with the source of every setting (secrets are redacted)
+func (a *Action) effectiveConfig(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		a.Config.Effective(), []string{"config_file", "environment", "settings"})
+}
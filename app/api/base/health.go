@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/bitcoin-sv/alert-system/app"
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bitcoin-sv/alert-system/app/models"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
 	"github.com/julienschmidt/httprouter"
@@ -19,6 +20,7 @@ type HealthResponse struct {
 	Synced            bool                `json:"synced"`
 	ActivePeers       int                 `json:"active_peers"`
 	UnprocessedAlerts int                 `json:"unprocessed_alerts"`
+	Nodes             []config.NodeHealth `json:"nodes"`
 }
 
 // health will return the health of the API and the current alert
@@ -36,6 +38,12 @@ func (a *Action) health(w http.ResponseWriter, req *http.Request, _ httprouter.P
 
 	failed, _ := models.GetAllUnprocessedAlerts(req.Context(), nil, model.WithAllDependencies(a.Config))
 
+	// The health of the nodes (from the last call or probe)
+	nodes := make([]config.NodeHealth, 0)
+	if pool, ok := a.Config.Services.Node.(*config.NodePool); ok {
+		nodes = pool.Health()
+	}
+
 	// Return the response
 	_ = apirouter.ReturnJSONEncode(
 		w,
@@ -46,6 +54,7 @@ func (a *Action) health(w http.ResponseWriter, req *http.Request, _ httprouter.P
 			Sequence:          alert.SequenceNumber,
 			ActivePeers:       a.P2pServer.ActivePeers(),
 			UnprocessedAlerts: len(failed),
-			Synced:            true, // TODO actually fetch this state from the DB somehow, or from the server struct
-		}, []string{"alert", "synced", "sequence", "active_peers", "unprocessed_alerts"})
+			Nodes:             nodes,
+			Synced:            a.P2pServer.Synced(),
+		}, []string{"alert", "synced", "sequence", "active_peers", "unprocessed_alerts", "nodes"})
 }
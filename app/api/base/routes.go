@@ -23,8 +23,8 @@ func RegisterRoutes(router *apirouter.Router, conf *config.Config, p2pServ *p2p.
 	// Set the main index page (navigating to slash or the root of the major version)
 	router.HTTPRouter.GET("/", action.Request(router, action.index))
 
-	// Options request (for CORs)
-	router.HTTPRouter.OPTIONS("/", router.SetCrossOriginHeaders)
+	// Options request (preflight requests are answered by the CORS policy of the web server)
+	router.HTTPRouter.OPTIONS("/", app.Head)
 
 	// Head requests are sometimes used for CORs
 	router.HTTPRouter.HEAD("/", app.Head)
@@ -41,6 +41,24 @@ func RegisterRoutes(router *apirouter.Router, conf *config.Config, p2pServ *p2p.
 	// Set the get alerts request
 	router.HTTPRouter.GET("/alerts", action.Request(router, action.alerts))
 
+	// Set the alert stream (server-sent events, not wrapped so the response can be flushed)
+	router.HTTPRouter.GET("/alerts/stream", action.alertStream)
+
 	// Set the get alert request
 	router.HTTPRouter.GET("/alert/:sequence", action.Request(router, action.alert))
+
+	// Set the enforcement schedule request (freeze and confiscation windows at the block height)
+	router.HTTPRouter.GET("/schedule", action.Request(router, action.schedule))
+
+	// The admin requests are only served with an admin token (web_server.admin_token)
+	if len(conf.WebServer.AdminToken) == 0 {
+		return
+	}
+
+	// Set the webhook delivery requests (admin)
+	router.HTTPRouter.GET("/webhooks/deliveries", action.AdminRequest(router, action.webhookDeliveries))
+	router.HTTPRouter.POST("/webhooks/deliveries/:id/replay", action.AdminRequest(router, action.webhookReplay))
+
+	// Set the effective configuration request (admin)
+	router.HTTPRouter.GET("/admin/config", action.AdminRequest(router, action.effectiveConfig))
 }
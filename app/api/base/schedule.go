@@ -0,0 +1,57 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+)
+
+// ScheduleResponse is the response for the enforcement schedule endpoint
+type ScheduleResponse struct {
+	Active          []*models.EnforcementWindow `json:"active"`
+	Expired         []*models.EnforcementWindow `json:"expired"`
+	Height          int64                       `json:"height"`
+	HeightUpdatedAt time.Time                   `json:"height_updated_at"`
+	Upcoming        []*models.EnforcementWindow `json:"upcoming"`
+}
+
+// schedule will return the enforcement windows of the alerts at the block height of the node
+func (a *Action) schedule(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+
+	// The height is followed by the chain height cron (get it from the node if it is not known yet)
+	height, updatedAt := a.Config.Services.Chain.Height()
+	if updatedAt.IsZero() {
+		if _, _, err := a.Config.Services.Chain.Update(req.Context(), a.Config.Services.Node); err != nil {
+			app.APIErrorResponse(w, req, http.StatusServiceUnavailable, fmt.Errorf("the block height of the node is unknown: %w", err))
+			return
+		}
+		height, updatedAt = a.Config.Services.Chain.Height()
+	}
+
+	// Get the schedule
+	schedule, err := models.GetEnforcementSchedule(req.Context(), height, model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		ScheduleResponse{
+			Active:          schedule.Active,
+			Expired:         schedule.Expired,
+			Height:          schedule.Height,
+			HeightUpdatedAt: updatedAt,
+			Upcoming:        schedule.Upcoming,
+		}, []string{"active", "expired", "height", "height_updated_at", "upcoming"})
+}
@@ -0,0 +1,129 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app"
+	"github.com/bitcoin-sv/alert-system/app/events"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Stream settings
+const (
+	streamKeepAliveInterval = 15 * time.Second // Comment line sent to keep proxies from closing the connection
+	streamRetry             = 5000             // Milliseconds a client waits before reconnecting
+)
+
+// streamResumeSequence returns the sequence the client has already seen (false if it is a new client)
+//
+// EventSource sends the Last-Event-ID header when it reconnects, the since
+// query parameter can be used by clients that keep track themselves.
+func streamResumeSequence(req *http.Request) (uint32, bool, error) {
+	since := req.Header.Get("Last-Event-ID")
+	if len(since) == 0 {
+		since = req.URL.Query().Get("since")
+	}
+	if len(since) == 0 {
+		return 0, false, nil
+	}
+	sequence, err := strconv.ParseUint(since, 10, 32)
+	if err != nil {
+		return 0, false, errors.New("since is invalid")
+	}
+	return uint32(sequence), true, nil
+}
+
+// writeAlertEvent will write an alert as a server-sent event (the id is the sequence)
+func writeAlertEvent(w io.Writer, event *events.AlertEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: alert\ndata: %s\n\n", event.Sequence, data)
+	return err
+}
+
+// alertStream will stream accepted alerts as server-sent events
+//
+// A resuming client first gets every alert after its last sequence from the datastore,
+// then new alerts as they are accepted from gossip or sync.
+func (a *Action) alertStream(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if a.P2pServer == nil || a.P2pServer.Events() == nil {
+		app.APIErrorResponse(w, req, http.StatusServiceUnavailable, errors.New("alert stream is not available"))
+		return
+	}
+
+	// Read the resume point
+	lastSequence, resume, err := streamResumeSequence(req)
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	// Subscribe before reading the datastore so nothing is missed in between
+	alerts, unsubscribe := a.P2pServer.Events().Subscribe(events.DefaultSubscriberBuffer)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err = fmt.Fprintf(w, "retry: %d\n\n", streamRetry); err != nil {
+		return
+	}
+	_ = rc.Flush()
+
+	// Send what the client missed
+	if resume {
+		var missed []*events.AlertEvent
+		if missed, err = events.GetAlertEventsAfterSequence(req.Context(), a.Config, lastSequence); err != nil {
+			a.Config.Services.Log.Errorf("failed to get alerts after sequence %d: %s", lastSequence, err.Error())
+			return
+		}
+		for _, event := range missed {
+			if err = writeAlertEvent(w, event); err != nil {
+				return
+			}
+			lastSequence = event.Sequence
+		}
+		_ = rc.Flush()
+	}
+
+	// Send new alerts
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err = io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			_ = rc.Flush()
+		case event, ok := <-alerts:
+			if !ok { // Fell behind, the client will reconnect and resume
+				return
+			}
+			if resume && event.Sequence <= lastSequence {
+				continue
+			}
+			if err = writeAlertEvent(w, event); err != nil {
+				return
+			}
+			_ = rc.Flush()
+			lastSequence, resume = event.Sequence, true
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package base
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStreamResumeSequence will test the method streamResumeSequence()
+func TestStreamResumeSequence(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new client", func(t *testing.T) {
+		sequence, resume, err := streamResumeSequence(httptest.NewRequest(http.MethodGet, "/alerts/stream", nil))
+		require.NoError(t, err)
+		assert.False(t, resume)
+		assert.Equal(t, uint32(0), sequence)
+	})
+
+	t.Run("last event id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/alerts/stream?since=2", nil)
+		req.Header.Set("Last-Event-ID", "12")
+		sequence, resume, err := streamResumeSequence(req)
+		require.NoError(t, err)
+		assert.True(t, resume)
+		assert.Equal(t, uint32(12), sequence)
+	})
+
+	t.Run("since", func(t *testing.T) {
+		sequence, resume, err := streamResumeSequence(httptest.NewRequest(http.MethodGet, "/alerts/stream?since=0", nil))
+		require.NoError(t, err)
+		assert.True(t, resume)
+		assert.Equal(t, uint32(0), sequence)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := streamResumeSequence(httptest.NewRequest(http.MethodGet, "/alerts/stream?since=-1", nil))
+		require.Error(t, err)
+	})
+}
+
+// TestWriteAlertEvent will test the method writeAlertEvent()
+func TestWriteAlertEvent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, writeAlertEvent(&buf, &events.AlertEvent{
+		Alert:    []byte(`{"message_length":5}`),
+		Sequence: 7,
+	}))
+	assert.Equal(t,
+		"id: 7\nevent: alert\ndata: {\"alert\":{\"message_length\":5},\"alert_type\":0,\"alert_type_name\":\"\",\"hash\":\"\",\"message\":\"\",\"processed\":false,\"raw\":\"\",\"sequence\":7}\n\n",
+		buf.String(),
+	)
+}
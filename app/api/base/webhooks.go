@@ -0,0 +1,125 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/app/webhook"
+	"github.com/julienschmidt/httprouter"
+	apirouter "github.com/mrz1836/go-api-router"
+)
+
+// WebhookDeliveriesResponse is the response for the webhook deliveries endpoint
+type WebhookDeliveriesResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`
+	Status     string                     `json:"status"`
+}
+
+// WebhookDeliveryResponse is a webhook delivery in the responses, without the URL (it can have credentials,
+// the endpoint is the name of the subscriber)
+type WebhookDeliveryResponse struct {
+	AlertHash      string    `json:"alert_hash"`
+	Attempts       uint32    `json:"attempts"`
+	Endpoint       string    `json:"endpoint"`
+	ID             uint64    `json:"id"`
+	LastError      string    `json:"last_error"`
+	NextAttemptAt  time.Time `json:"next_attempt_at"`
+	Payload        string    `json:"payload"`
+	SequenceNumber uint32    `json:"sequence_number"`
+	Status         string    `json:"status"`
+}
+
+// newWebhookDeliveryResponse will create the response of the webhook delivery
+func newWebhookDeliveryResponse(delivery *models.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		AlertHash:      delivery.AlertHash,
+		Attempts:       delivery.Attempts,
+		Endpoint:       delivery.Endpoint,
+		ID:             delivery.ID,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		Payload:        delivery.Payload,
+		SequenceNumber: delivery.SequenceNumber,
+		Status:         string(delivery.Status),
+	}
+}
+
+// webhookDeliveries will return the webhook deliveries with the given status (defaults to dead)
+func (a *Action) webhookDeliveries(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+
+	// Read the status param
+	status := models.WebhookDeliveryStatusDead
+	if params := apirouter.GetParams(req); params != nil {
+		if s := params.GetString("status"); len(s) > 0 {
+			status = models.WebhookDeliveryStatus(s)
+		}
+	}
+	switch status {
+	case models.WebhookDeliveryStatusDead, models.WebhookDeliveryStatusDelivered, models.WebhookDeliveryStatusPending:
+	default:
+		app.APIErrorResponse(w, req, http.StatusBadRequest, errors.New("status is invalid"))
+		return
+	}
+
+	// Get the deliveries
+	deliveries, err := models.GetWebhookDeliveriesByStatus(req.Context(), status, nil, model.WithAllDependencies(a.Config))
+	if err != nil {
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Return the response
+	response := WebhookDeliveriesResponse{
+		Deliveries: make([]*WebhookDeliveryResponse, 0, len(deliveries)),
+		Status:     string(status),
+	}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, newWebhookDeliveryResponse(delivery))
+	}
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		response, []string{"deliveries", "status"})
+}
+
+// webhookReplay will reset a webhook delivery and attempt it again
+func (a *Action) webhookReplay(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	// Read params
+	params := apirouter.GetParams(req)
+	if params == nil {
+		apiError := apirouter.ErrorFromRequest(req, "parameters is nil", "no parameters specified", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return
+	}
+	id, err := strconv.ParseUint(params.GetString("id"), 10, 64)
+	if err != nil {
+		apiError := apirouter.ErrorFromRequest(req, "id is invalid", "id is invalid", http.StatusBadRequest, http.StatusBadRequest, "")
+		apirouter.ReturnResponse(w, req, apiError.Code, apiError)
+		return
+	}
+
+	// Replay the delivery
+	var delivery *models.WebhookDelivery
+	if delivery, err = webhook.Replay(req.Context(), a.Config, id); err != nil {
+		if errors.Is(err, webhook.ErrDeliveryNotFound) {
+			app.APIErrorResponse(w, req, http.StatusNotFound, err)
+			return
+		}
+		app.APIErrorResponse(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Return the response
+	_ = apirouter.ReturnJSONEncode(
+		w,
+		http.StatusOK,
+		json.NewEncoder(w),
+		newWebhookDeliveryResponse(delivery), []string{"id", "alert_hash", "sequence_number", "endpoint", "status", "attempts", "next_attempt_at", "last_error"})
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bitcoin networks (the bitcoin.conf section names)
+const (
+	bitcoinNetworkMain    = "main"
+	bitcoinNetworkRegtest = "regtest"
+	bitcoinNetworkSTN     = "stn"
+	bitcoinNetworkTest    = "test"
+)
+
+// bitcoinNetworkDefaults are the default data directory (under the datadir) and RPC port of the networks
+var bitcoinNetworkDefaults = map[string]struct {
+	dataDir string
+	rpcPort string
+}{
+	bitcoinNetworkMain:    {dataDir: "", rpcPort: "8332"},
+	bitcoinNetworkRegtest: {dataDir: "regtest", rpcPort: "18332"},
+	bitcoinNetworkSTN:     {dataDir: "stn", rpcPort: "9332"},
+	bitcoinNetworkTest:    {dataDir: "testnet3", rpcPort: "18332"},
+}
+
+// bitcoinCookieUser is the user in the cookie file
+const bitcoinCookieUser = "__cookie__"
+
+// bitcoinConf is a parsed bitcoin.conf file (and the files it includes)
+type bitcoinConf struct {
+	network  string                         // main, test, regtest or stn
+	path     string                         // Path of the bitcoin.conf file
+	sections map[string]map[string][]string // Values by section and option ("" is the top level)
+}
+
+// parseBitcoinConf will parse the bitcoin.conf file like bitcoind: # comments, [main], [test], [regtest]
+// and [stn] sections (or a network prefix: test.rpcport), repeated options and includeconf
+func parseBitcoinConf(path string) (*bitcoinConf, error) {
+	conf := &bitcoinConf{path: path, sections: map[string]map[string][]string{"": {}}}
+	if err := conf.parseFile(path); err != nil {
+		return nil, err
+	}
+
+	// The included files are relative to the data directory (an includeconf in an included file is ignored)
+	for _, include := range conf.sections[""]["includeconf"] {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(conf.dataDir(), include)
+		}
+		if err := conf.parseFile(include); err != nil {
+			return nil, err
+		}
+	}
+
+	// The network decides which section is used
+	top := conf.sections[""]
+	switch {
+	case len(conf.last(top["chain"])) > 0:
+		conf.network = conf.last(top["chain"])
+	case isBitcoinConfTrue(conf.last(top["regtest"])):
+		conf.network = bitcoinNetworkRegtest
+	case isBitcoinConfTrue(conf.last(top["testnet"])):
+		conf.network = bitcoinNetworkTest
+	case isBitcoinConfTrue(conf.last(top["stn"])):
+		conf.network = bitcoinNetworkSTN
+	default:
+		conf.network = bitcoinNetworkMain
+	}
+	if _, ok := bitcoinNetworkDefaults[conf.network]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBitcoinConfNetwork, conf.network)
+	}
+	return conf, nil
+}
+
+// parseFile will parse the options in the file (includeconf is only kept from the bitcoin.conf file)
+func (b *bitcoinConf) parseFile(path string) error {
+	file, err := os.Open(path) //nolint:gosec // The path is set by the operator
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	isIncluded := path != b.path
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+
+		// Remove the comment and the surrounding whitespace
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+
+		// A section ([test]) applies to the options after it
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		// The value is everything after the first = (it can contain =)
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("%w: %s line %d: %s", ErrBitcoinConfParse, path, lineNumber, line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		// A network prefix is the same as a section (test.rpcport=18332)
+		keySection := section
+		if prefix, name, hasPrefix := strings.Cut(key, "."); hasPrefix {
+			keySection, key = prefix, name
+		}
+		if key == "includeconf" && (isIncluded || len(keySection) > 0) {
+			continue
+		}
+		if b.sections[keySection] == nil {
+			b.sections[keySection] = make(map[string][]string)
+		}
+		b.sections[keySection][key] = append(b.sections[keySection][key], value)
+	}
+	return scanner.Err()
+}
+
+// values returns all the values of the option (the network section overrides the top level)
+func (b *bitcoinConf) values(key string) []string {
+	if values, ok := b.sections[b.network][key]; ok {
+		return values
+	}
+	return b.sections[""][key]
+}
+
+// get returns the value of the option (the last one if it is repeated)
+func (b *bitcoinConf) get(key string) string {
+	return b.last(b.values(key))
+}
+
+// last returns the last value (bitcoind uses the last value of a repeated option)
+func (b *bitcoinConf) last(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// dataDir returns the data directory (datadir, or the directory of bitcoin.conf)
+func (b *bitcoinConf) dataDir() string {
+	if dataDir := b.last(b.sections[""]["datadir"]); len(dataDir) > 0 {
+		return dataDir
+	}
+	return filepath.Dir(b.path)
+}
+
+// cookieFile returns the path of the cookie file (rpccookiefile is relative to the network data directory)
+func (b *bitcoinConf) cookieFile() string {
+	cookieFile := b.get("rpccookiefile")
+	if len(cookieFile) == 0 {
+		cookieFile = ".cookie"
+	}
+	if filepath.IsAbs(cookieFile) {
+		return cookieFile
+	}
+	return filepath.Join(b.dataDir(), bitcoinNetworkDefaults[b.network].dataDir, cookieFile)
+}
+
+// isBitcoinConfTrue returns true if a boolean option is set (testnet=1)
+func isBitcoinConfTrue(value string) bool {
+	return len(value) > 0 && value != "0"
+}
+
+// readCookieFile will read the RPC user and password from the cookie file (__cookie__:<password>)
+func readCookieFile(path string) (user, password string, err error) {
+	var b []byte
+	if b, err = os.ReadFile(path); err != nil { //nolint:gosec // The path is set by the operator
+		return "", "", err
+	}
+	user, password, _ = strings.Cut(strings.TrimSpace(string(b)), ":")
+	if user != bitcoinCookieUser || len(password) == 0 {
+		return "", "", fmt.Errorf("%s is not a cookie file", path)
+	}
+	return user, password, nil
+}
+
+// loadBitcoinConfiguration will load the RPC connection from bitcoin.conf: the host and port (rpcconnect and
+// rpcport), and the credentials (rpcuser and rpcpassword, rpcauth or the path of the cookie file)
+//
+// The first RPC connection has the defaults: the host and port (otherwise localhost and the network RPC port),
+// the user to choose the rpcauth entry and the password for it (rpcauth only has a hash of the password),
+// and the timeout. The loaded connection replaces the first RPC connection (the other ones are kept).
+func (c *Config) loadBitcoinConfiguration() error {
+	if len(c.BitcoinConfigPath) == 0 {
+		return nil
+	}
+	c.Services.Log.Infof("loading RPC configuration from %s", c.BitcoinConfigPath)
+	conf, err := parseBitcoinConf(c.BitcoinConfigPath)
+	if err != nil {
+		return err
+	}
+
+	// The defaults from the first RPC connection
+	host, port := "localhost", bitcoinNetworkDefaults[conf.network].rpcPort
+	var defaultUser, defaultPassword string
+	timeout := DefaultRPCTimeout
+	if len(c.RPCConnections) > 0 {
+		defaultUser, defaultPassword, timeout = c.RPCConnections[0].User, c.RPCConnections[0].Password, c.RPCConnections[0].Timeout
+		if u, parseErr := url.Parse(c.RPCConnections[0].Host); parseErr == nil && len(u.Hostname()) > 0 {
+			host = u.Hostname()
+			if len(u.Port()) > 0 {
+				port = u.Port()
+			}
+		}
+	}
+
+	// The host and port (rpcconnect can have the port)
+	if rpcConnect := conf.get("rpcconnect"); len(rpcConnect) > 0 {
+		host = rpcConnect
+		if splitHost, splitPort, splitErr := net.SplitHostPort(rpcConnect); splitErr == nil {
+			host, port = splitHost, splitPort
+		}
+		host = strings.Trim(host, "[]")
+	} else {
+		c.Services.Log.Debugf("rpcconnect value not detected in bitcoin.conf")
+	}
+	if rpcPort := conf.get("rpcport"); len(rpcPort) > 0 {
+		port = rpcPort
+	} else {
+		c.Services.Log.Debugf("rpcport value not detected in bitcoin.conf")
+	}
+
+	// The credentials (bitcoind uses the cookie file if there is no rpcpassword)
+	var cookieFile string
+	user, password := conf.get("rpcuser"), conf.get("rpcpassword")
+	switch {
+	case len(user) > 0 && len(password) > 0:
+	case len(conf.values("rpcauth")) > 0:
+		if len(defaultPassword) == 0 {
+			return ErrBitcoinConfNoPassword
+		}
+		// rpcauth=<user>:<salt>$<hash>, use the entry of the configured user (or the first one)
+		user, _, _ = strings.Cut(conf.values("rpcauth")[0], ":")
+		for _, rpcAuth := range conf.values("rpcauth") {
+			if authUser, _, _ := strings.Cut(rpcAuth, ":"); authUser == defaultUser {
+				user = authUser
+			}
+		}
+		password = defaultPassword
+	default:
+		// The cookie is read by the node client (it changes every time the node starts)
+		cookieFile, user, password = conf.cookieFile(), "", ""
+		c.Services.Log.Debugf("rpcuser and rpcpassword not detected in bitcoin.conf, using the cookie file %s", cookieFile)
+	}
+
+	connections := []RPCConfig{{
+		CookieFile: cookieFile,
+		Host:       "http://" + net.JoinHostPort(host, port),
+		Password:   password,
+		Timeout:    timeout,
+		User:       user,
+	}}
+	if len(c.RPCConnections) > 1 {
+		connections = append(connections, c.RPCConnections[1:]...)
+	}
+	c.RPCConnections = connections
+	return nil
+}
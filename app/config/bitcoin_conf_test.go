@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBitcoinConf will write the bitcoin.conf (and the other files) to a temporary data directory
+func writeTestBitcoinConf(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+	return filepath.Join(dir, "bitcoin.conf")
+}
+
+// newTestBitcoinConfig will create a config that loads the bitcoin.conf (with a default RPC connection)
+func newTestBitcoinConfig(t *testing.T, path string, connections ...RPCConfig) *Config {
+	c := &Config{BitcoinConfigPath: path, RPCConnections: connections}
+	require.NoError(t, c.loadLogger())
+	return c
+}
+
+// TestParseBitcoinConf tests the method parseBitcoinConf()
+func TestParseBitcoinConf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("comments, values with = and repeated options", func(t *testing.T) {
+		conf, err := parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "# The RPC settings\n\n  rpcuser = galt  \nrpcpassword=abc=def== # base64\nrpcport=1\nrpcport=2\n",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, bitcoinNetworkMain, conf.network)
+		assert.Equal(t, "galt", conf.get("rpcuser"))
+		assert.Equal(t, "abc=def==", conf.get("rpcpassword"))
+		assert.Equal(t, "2", conf.get("rpcport"))
+		assert.Empty(t, conf.get("rpcconnect"))
+	})
+
+	t.Run("network sections and prefixes", func(t *testing.T) {
+		conf, err := parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "testnet=1\nrpcport=8332\nrpcuser=galt\nmain.rpcuser=main\n[test]\nrpcport=18332\n[main]\nrpcport=8000\n",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, bitcoinNetworkTest, conf.network)
+		assert.Equal(t, "18332", conf.get("rpcport"))
+		assert.Equal(t, "galt", conf.get("rpcuser"))
+
+		conf, err = parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{"bitcoin.conf": "chain=regtest\n"}))
+		require.NoError(t, err)
+		assert.Equal(t, bitcoinNetworkRegtest, conf.network)
+	})
+
+	t.Run("includeconf", func(t *testing.T) {
+		conf, err := parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "includeconf=rpc.conf\nrpcuser=galt\n",
+			"rpc.conf":     "rpcpassword=included\nincludeconf=ignored.conf\n",
+			"ignored.conf": "rpcport=1\n",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "included", conf.get("rpcpassword"))
+		assert.Empty(t, conf.get("rpcport"))
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		_, err := parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{"bitcoin.conf": "rpcuser=galt\nrpcpassword\n"}))
+		require.ErrorIs(t, err, ErrBitcoinConfParse)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		_, err := parseBitcoinConf(writeTestBitcoinConf(t, map[string]string{"bitcoin.conf": "chain=signet\n"}))
+		require.ErrorIs(t, err, ErrBitcoinConfNetwork)
+		assert.Contains(t, err.Error(), "signet")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := parseBitcoinConf(filepath.Join(t.TempDir(), "bitcoin.conf"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// TestConfig_loadBitcoinConfiguration tests the method loadBitcoinConfiguration()
+func TestConfig_loadBitcoinConfiguration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user and password", func(t *testing.T) {
+		c := newTestBitcoinConfig(t, writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "rpcuser=galt\nrpcpassword=pass=word\nrpcconnect=10.0.0.1\n",
+		}), RPCConfig{Host: "http://localhost:8333"})
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, []RPCConfig{{Host: "http://10.0.0.1:8333", Password: "pass=word", User: "galt"}}, c.RPCConnections)
+	})
+
+	t.Run("the other RPC connections are kept", func(t *testing.T) {
+		c := newTestBitcoinConfig(t, writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "rpcuser=galt\nrpcpassword=galt\n",
+		}), RPCConfig{Host: "http://localhost:8333"}, RPCConfig{Host: "http://10.0.0.2:8332", Password: "other", User: "other"})
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, []RPCConfig{
+			{Host: "http://localhost:8333", Password: "galt", User: "galt"},
+			{Host: "http://10.0.0.2:8332", Password: "other", User: "other"},
+		}, c.RPCConnections)
+	})
+
+	t.Run("network defaults without an RPC connection", func(t *testing.T) {
+		c := newTestBitcoinConfig(t, writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "stn=1\nrpcuser=galt\nrpcpassword=galt\n[stn]\nrpcconnect=[::1]\n",
+		}))
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, "http://[::1]:9332", c.RPCConnections[0].Host)
+	})
+
+	t.Run("cookie file", func(t *testing.T) {
+		c := newTestBitcoinConfig(t, writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf":     "testnet=1\n",
+			"testnet3/.cookie": "__cookie__:cookie-password\n",
+		}))
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, []RPCConfig{{
+			CookieFile: filepath.Join(filepath.Dir(c.BitcoinConfigPath), "testnet3", ".cookie"),
+			Host:       "http://localhost:18332",
+			Timeout:    DefaultRPCTimeout,
+		}}, c.RPCConnections)
+
+		// A missing cookie file (the node is not running yet, the cookie is read by the node client)
+		c = newTestBitcoinConfig(t, writeTestBitcoinConf(t, map[string]string{"bitcoin.conf": "rpccookiefile=/missing/.cookie\n"}))
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, "/missing/.cookie", c.RPCConnections[0].CookieFile)
+	})
+
+	t.Run("rpcauth", func(t *testing.T) {
+		path := writeTestBitcoinConf(t, map[string]string{
+			"bitcoin.conf": "rpcport=8332\nrpcauth=first:salt$hash\nrpcauth=galt:salt$hash\n",
+		})
+		c := newTestBitcoinConfig(t, path, RPCConfig{Host: "http://localhost:8333", Password: "galt-password", Timeout: 5 * time.Second, User: "galt"})
+		require.NoError(t, c.loadBitcoinConfiguration())
+		assert.Equal(t, []RPCConfig{{Host: "http://localhost:8332", Password: "galt-password", Timeout: 5 * time.Second, User: "galt"}}, c.RPCConnections)
+
+		// The password is required (rpcauth only has a hash)
+		c = newTestBitcoinConfig(t, path, RPCConfig{Host: "http://localhost:8333"})
+		require.ErrorIs(t, c.loadBitcoinConfiguration(), ErrBitcoinConfNoPassword)
+	})
+}
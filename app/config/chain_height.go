@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ChainHeight follows the block height of the best chain of the node (updated by Update, see
+// chain_height_interval)
+type ChainHeight struct {
+	height    int64        // The last height of the node
+	lock      sync.RWMutex // Guards the height
+	updatedAt time.Time    // When the height was updated (zero: never)
+}
+
+// Height returns the last block height of the node, and when it was updated (zero: the height is unknown)
+func (c *ChainHeight) Height() (int64, time.Time) {
+	if c == nil {
+		return 0, time.Time{}
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.height, c.updatedAt
+}
+
+// Update will get the block height from the node, and return the previous and the new height (the
+// previous height is -1 if it was unknown, nothing is updated without a ChainHeight)
+func (c *ChainHeight) Update(ctx context.Context, node NodeInterface) (previous, height int64, err error) {
+	if c == nil {
+		return -1, 0, nil
+	}
+	if height, err = node.BlockCount(ctx); err != nil {
+		return -1, 0, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	previous = c.height
+	if c.updatedAt.IsZero() {
+		previous = -1
+	}
+	c.height = height
+	c.updatedAt = time.Now().UTC()
+	return previous, height, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChainHeight tests following the block height with the ChainHeight
+func TestChainHeight(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown before the first update", func(t *testing.T) {
+		var c *ChainHeight
+		height, updatedAt := c.Height()
+		assert.Equal(t, int64(0), height)
+		assert.True(t, updatedAt.IsZero())
+
+		height, updatedAt = (&ChainHeight{}).Height()
+		assert.Equal(t, int64(0), height)
+		assert.True(t, updatedAt.IsZero())
+	})
+
+	t.Run("nothing is updated without a chain height", func(t *testing.T) {
+		var c *ChainHeight
+		node := &mocks.Node{BlockCountFunc: func(context.Context) (int64, error) { return 100, nil }}
+		previous, height, err := c.Update(ctx, node)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), previous)
+		assert.Equal(t, int64(0), height)
+	})
+
+	t.Run("previous and new height", func(t *testing.T) {
+		blockCount := int64(100)
+		node := &mocks.Node{BlockCountFunc: func(context.Context) (int64, error) { return blockCount, nil }}
+		c := &ChainHeight{}
+
+		previous, height, err := c.Update(ctx, node)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), previous)
+		assert.Equal(t, int64(100), height)
+
+		blockCount = 101
+		previous, height, err = c.Update(ctx, node)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), previous)
+		assert.Equal(t, int64(101), height)
+
+		current, updatedAt := c.Height()
+		assert.Equal(t, int64(101), current)
+		assert.False(t, updatedAt.IsZero())
+	})
+
+	t.Run("the height is kept when the node fails", func(t *testing.T) {
+		c := &ChainHeight{}
+		_, _, err := c.Update(ctx, &mocks.Node{BlockCountFunc: func(context.Context) (int64, error) { return 100, nil }})
+		require.NoError(t, err)
+
+		_, _, err = c.Update(ctx, &mocks.Node{BlockCountFunc: func(context.Context) (int64, error) { return 0, errTestNodeDown }})
+		require.ErrorIs(t, err, errTestNodeDown)
+		height, _ := c.Height()
+		assert.Equal(t, int64(100), height)
+	})
+}
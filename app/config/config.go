@@ -4,8 +4,10 @@ package config
 import (
 	"embed"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/bsv-blockchain/go-bn"
 	"github.com/mrz1836/go-datastore"
 )
 
@@ -50,36 +52,111 @@ var (
 	LocalPrivateKeyDirectory       = ".bitcoin"                    // Default local private key directory
 )
 
+// Default CORS policy values (origins are empty by default: same-origin only)
+var (
+	DefaultCORSAllowedHeaders = []string{"Content-Type"}                                      // Default headers allowed on cross-origin requests
+	DefaultCORSAllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions} // Default methods allowed on cross-origin requests
+)
+
+// Webhook endpoint formats
+const (
+	WebhookFormatEmail = "email" // Plain text email sent over SMTP
+	WebhookFormatJSON  = "json"  // The default JSON payload
+	WebhookFormatSlack = "slack" // Slack incoming webhook blocks
+	WebhookFormatTeams = "teams" // Microsoft Teams adaptive card
+)
+
+// Default webhook delivery values
+var (
+	DefaultWebhookDeliveryInterval = 30 * time.Second // Default interval for checking the webhook outbox
+	DefaultWebhookEndpointName     = "default"        // Name of the endpoint created from alert_webhook_url
+	DefaultWebhookInitialBackoff   = 30 * time.Second // Default delay before the first webhook retry
+	DefaultWebhookMaxAttempts      = uint32(10)       // Default attempts before a webhook delivery is dead-lettered
+	DefaultWebhookMaxBackoff       = 1 * time.Hour    // Default maximum delay between webhook retries
+	DefaultWebhookTimeout          = 10 * time.Second // Default timeout for a single webhook request
+)
+
+// Default node RPC values
+var (
+	DefaultAlertVerificationInterval = 30 * time.Minute // Default interval for checking the effect of the processed alerts on the node
+	DefaultChainHeightInterval       = 1 * time.Minute  // Default interval for following the block height of the node
+	DefaultNodeCircuitOpenTimeout    = 1 * time.Minute  // Default time a node's circuit stays open before a call is tried again
+	DefaultNodeFailureThreshold      = 3                // Default consecutive failures before a node's circuit opens
+	DefaultNodeProbeInterval         = 30 * time.Second // Default interval for probing the liveness of the nodes
+	DefaultRPCTimeout                = 30 * time.Second // Default timeout for a single RPC request
+)
+
+// DefaultGRPCPort is the default port for the gRPC server
+var DefaultGRPCPort = "9907"
+
 // The global configuration settings
 type (
 
 	// Config is the global configuration settings
 	Config struct {
-		AlertWebhookURL         string          `json:"alert_webhook_url" mapstructure:"alert_webhook_url"`                 // AlertWebhookURL is the URL for the alert webhook
-		GenesisKeys             []string        `json:"genesis_keys" mapstructure:"genesis_keys"`                           // GenesisKeys is a list of public keys to use for the genesis alert
-		Datastore               DatastoreConfig `json:"datastore" mapstructure:"datastore"`                                 // Datastore's configuration
-		DisableRPCVerification  bool            `json:"disable_rpc_verification" mapstructure:"disable_rpc_verification"`   // DisableRPCVerification will disable the rpc verification check on startup. Useful if bitcoind isn't running yet
-		LogOutputFile           string          `json:"log_output_file" mapstructure:"log_output_file"`                     // LogOutputFile will set an output file for the logger to write to as opposed to stdout
-		LogLevel                string          `json:"log_level" mapstructure:"log_level"`                                 // LogLevel sets the logging level
-		BitcoinConfigPath       string          `json:"bitcoin_config_path" mapstructure:"bitcoin_config_path"`             // BitcoinConfigPath is the path to the bitcoin.conf file
-		P2P                     P2PConfig       `json:"p2p" mapstructure:"p2p"`                                             // P2P is the configuration for the P2P server
-		RPCConnections          []RPCConfig     `json:"rpc_connections" mapstructure:"rpc_connections"`                     // RPCConnections is a list of RPC connections
-		RequestLogging          bool            `json:"request_logging" mapstructure:"request_logging"`                     // Toggle for verbose request logging (API requests)
-		Services                Services        `json:"-" mapstructure:"services"`                                          // Services is the global services
-		WebServer               WebServerConfig `json:"web_server" mapstructure:"web_server"`                               // WebServer is the configuration for the web HTTP Server
-		AlertProcessingInterval time.Duration   `json:"alert_processing_interval" mapstructure:"alert_processing_interval"` // AlertProcessingInterval is the interval in which the system will go through all the saved alerts and attempt to retry any unprocessed alerts
+		AlertWebhookURL           string            `json:"alert_webhook_url" mapstructure:"alert_webhook_url"`                     // AlertWebhookURL is the URL for the alert webhook
+		GRPC                      GRPCConfig        `json:"grpc" mapstructure:"grpc"`                                               // GRPC is the configuration for the gRPC server
+		GenesisKeys               []string          `json:"genesis_keys" mapstructure:"genesis_keys"`                               // GenesisKeys is a list of public keys to use for the genesis alert
+		Datastore                 DatastoreConfig   `json:"datastore" mapstructure:"datastore"`                                     // Datastore's configuration
+		DisableRPCVerification    bool              `json:"disable_rpc_verification" mapstructure:"disable_rpc_verification"`       // DisableRPCVerification will disable the rpc verification check on startup. Useful if bitcoind isn't running yet
+		LogOutputFile             string            `json:"log_output_file" mapstructure:"log_output_file"`                         // LogOutputFile will set an output file for the logger to write to as opposed to stdout
+		LogLevel                  string            `json:"log_level" mapstructure:"log_level"`                                     // LogLevel sets the logging level
+		BitcoinConfigPath         string            `json:"bitcoin_config_path" mapstructure:"bitcoin_config_path"`                 // BitcoinConfigPath is the path to the bitcoin.conf file
+		P2P                       P2PConfig         `json:"p2p" mapstructure:"p2p"`                                                 // P2P is the configuration for the P2P server
+		RPCConnections            []RPCConfig       `json:"rpc_connections" mapstructure:"rpc_connections"`                         // RPCConnections is a list of RPC connections
+		NodeHealth                NodeHealthConfig  `json:"node_health" mapstructure:"node_health"`                                 // NodeHealth is the configuration for the node liveness probes and circuit breakers
+		RequestLogging            bool              `json:"request_logging" mapstructure:"request_logging"`                         // Toggle for verbose request logging (API requests)
+		Services                  Services          `json:"-" mapstructure:"services"`                                              // Services is the global services
+		WebServer                 WebServerConfig   `json:"web_server" mapstructure:"web_server"`                                   // WebServer is the configuration for the web HTTP Server
+		Webhook                   WebhookConfig     `json:"webhook" mapstructure:"webhook"`                                         // Webhook is the configuration for the webhook delivery outbox
+		AlertProcessingInterval   time.Duration     `json:"alert_processing_interval" mapstructure:"alert_processing_interval"`     // AlertProcessingInterval is the interval in which the system will go through all the saved alerts and attempt to retry any unprocessed alerts
+		AlertVerificationInterval time.Duration     `json:"alert_verification_interval" mapstructure:"alert_verification_interval"` // AlertVerificationInterval is how often the effect of the processed alerts is checked on the node
+		ChainHeightInterval       time.Duration     `json:"chain_height_interval" mapstructure:"chain_height_interval"`             // ChainHeightInterval is how often the block height of the node is checked (for the enforcement schedule)
+		ConfigWatchInterval       time.Duration     `json:"config_watch_interval" mapstructure:"config_watch_interval"`             // ConfigWatchInterval is how often the custom config file is checked for changes to reload (0: only reload on SIGHUP)
+		Environment               string            `json:"environment" mapstructure:"environment"`                                 // Environment is the name of the environment the config file is for (informational)
+		configFile                string            // The config file that was read (see Effective)
+		reload                    *reloadState      // Reload state (see Reload)
+		secretProblems            ValidationErrors  // Secrets that could not be loaded (see Validate)
+		sources                   map[string]string // Where the settings were loaded from, by viper key (see Effective)
+		unknownSettings           []string          // Keys in the config file that are not settings (see Validate)
 	}
 
 	// DatastoreConfig is the configuration for the datastore
 	DatastoreConfig struct {
-		AutoMigrate bool                    `json:"auto_migrate" mapstructure:"auto_migrate"` // Loads a blank database
-		Debug       bool                    `json:"debug" mapstructure:"debug"`               // True for SQL statements
-		Engine      datastore.Engine        `json:"engine" mapstructure:"engine"`             // MySQL, Postgres, SQLite
-		Password    string                  `json:"password" mapstructure:"password"`         // Used for MySQL or Postgresql
-		SQLite      *datastore.SQLiteConfig `json:"sqlite" mapstructure:"sqlite"`             // Configuration for SQLite
-		SQLRead     *datastore.SQLConfig    `json:"sql_read" mapstructure:"sql_read"`         // Configuration for MySQL or Postgres
-		SQLWrite    *datastore.SQLConfig    `json:"sql_write" mapstructure:"sql_write"`       // Configuration for MySQL or Postgres
-		TablePrefix string                  `json:"table_prefix" mapstructure:"table_prefix"` // pre_table_name (pre)
+		AutoMigrate  bool                    `json:"auto_migrate" mapstructure:"auto_migrate"`   // Loads a blank database
+		Debug        bool                    `json:"debug" mapstructure:"debug"`                 // True for SQL statements
+		Engine       datastore.Engine        `json:"engine" mapstructure:"engine"`               // MySQL, Postgres, SQLite, MongoDB, kvstore
+		KVStore      *KVStoreConfig          `json:"kvstore" mapstructure:"kvstore"`             // Configuration for the embedded key-value store
+		Mongo        *MongoConfig            `json:"mongo" mapstructure:"mongo"`                 // Configuration for MongoDB
+		Password     string                  `json:"password" mapstructure:"password"`           // Used for MySQL or Postgresql
+		PasswordFile string                  `json:"password_file" mapstructure:"password_file"` // File with the password (instead of password)
+		SQLite       *datastore.SQLiteConfig `json:"sqlite" mapstructure:"sqlite"`               // Configuration for SQLite
+		SQLRead      *SQLConfig              `json:"sql_read" mapstructure:"sql_read"`           // Configuration for MySQL or Postgres
+		SQLWrite     *SQLConfig              `json:"sql_write" mapstructure:"sql_write"`         // Configuration for MySQL or Postgres
+		TablePrefix  string                  `json:"table_prefix" mapstructure:"table_prefix"`   // pre_table_name (pre)
+	}
+
+	// MongoConfig is the configuration for MongoDB (with the connection string in a file)
+	MongoConfig struct {
+		datastore.MongoDBConfig `json:",inline" mapstructure:",squash"`
+		URIFile                 string `json:"uri_file" mapstructure:"uri_file"` // File with the connection string (instead of uri)
+	}
+
+	// SQLConfig is the configuration for a MySQL or Postgres connection (with the password in a file)
+	SQLConfig struct {
+		datastore.SQLConfig `json:",inline" mapstructure:",squash"`
+		PasswordFile        string `json:"password_file" mapstructure:"password_file"` // File with the password (instead of password)
+	}
+
+	// KVStoreConfig is the configuration for the embedded key-value store
+	KVStoreConfig struct {
+		DatabasePath string `json:"database_path" mapstructure:"database_path"` // Path of the data file (alert_system_datastore.kv)
+	}
+
+	// GRPCConfig is the configuration for the gRPC server
+	GRPCConfig struct {
+		Enabled bool   `json:"enabled" mapstructure:"enabled"` // Serve the gRPC API alongside the web server
+		Port    string `json:"port" mapstructure:"port"`       // 9907
 	}
 
 	// HTTPInterface is used for the HTTP client
@@ -89,15 +166,27 @@ type (
 
 	// Node is the configuration and functions for interacting with a node
 	Node struct {
-		RPCHost     string `json:"rpc_host" mapstructure:"rpc_host"`         // RPCHost is the RPC host
-		RPCPassword string `json:"rpc_password" mapstructure:"rpc_password"` // RPCPassword is the RPC password
-		RPCUser     string `json:"rpc_user" mapstructure:"rpc_user"`         // RPCUser is the RPC username
+		RPCCookieFile string        `json:"rpc_cookie_file" mapstructure:"rpc_cookie_file"` // RPCCookieFile is the cookie file with the RPC user and password (if set)
+		RPCHost       string        `json:"rpc_host" mapstructure:"rpc_host"`               // RPCHost is the RPC host
+		RPCPassword   string        `json:"rpc_password" mapstructure:"rpc_password"`       // RPCPassword is the RPC password
+		RPCTimeout    time.Duration `json:"rpc_timeout" mapstructure:"rpc_timeout"`         // RPCTimeout is the timeout for a single RPC request (0: no timeout)
+		RPCUser       string        `json:"rpc_user" mapstructure:"rpc_user"`               // RPCUser is the RPC username
+		lock          sync.Mutex    // Guards the credentials (read from the cookie file) and the client
+		queryClient   *http.Client  // The HTTP client for the calls the client does not have (see query)
+		rpcClient     bn.NodeClient // The client, created on the first call (and again when the cookie changes)
+	}
+
+	// NodeHealthConfig is the configuration for the node liveness probes and circuit breakers
+	NodeHealthConfig struct {
+		CircuitOpenTimeout time.Duration `json:"circuit_open_timeout" mapstructure:"circuit_open_timeout"` // 1m (calls fail without calling the node until then)
+		FailureThreshold   int           `json:"failure_threshold" mapstructure:"failure_threshold"`       // 3 (consecutive failures that open the circuit)
+		ProbeInterval      time.Duration `json:"probe_interval" mapstructure:"probe_interval"`             // 30s
 	}
 
 	// P2PConfig is the configuration for the P2P server and connection
 	P2PConfig struct {
-		AlertSystemProtocolID string        `json:"alert_system_protocol_id" mapstructure:"alert_system_protocol_id"` // AlertSystemProtocolID is the protocol ID to use on the libp2p network for alert system communication
-		DHTMode               string        `json:"dht_mode"`
+		AlertSystemProtocolID string        `json:"alert_system_protocol_id" mapstructure:"alert_system_protocol_id"`     // AlertSystemProtocolID is the protocol ID to use on the libp2p network for alert system communication
+		DHTMode               string        `json:"dht_mode" mapstructure:"dht_mode"`                                     // DHTMode is the DHT mode: client or server (empty: automatic)
 		BootstrapPeer         string        `json:"bootstrap_peer" mapstructure:"bootstrap_peer"`                         // BootstrapPeer is the bootstrap peer for the libp2p network
 		BroadcastIP           string        `json:"broadcast_ip" mapstructure:"broadcast_ip"`                             // BroadcastIP is the public facing IP address to broadcast to other peers
 		IP                    string        `json:"ip" mapstructure:"ip"`                                                 // IP is the IP address for the P2P server
@@ -105,19 +194,25 @@ type (
 		AllowPrivateIPs       bool          `json:"allow_private_ip_addresses" mapstructure:"allow_private_ip_addresses"` // AllowPrivateIPs will disable the default behavior of filtering out private IP addresses
 		PrivateKeyPath        string        `json:"private_key_path" mapstructure:"private_key_path"`                     // PrivateKeyPath is the path to the private key
 		PrivateKey            string        `json:"private_key" mapstructure:"private_key"`                               // PrivateKey is a hex encoded private key to use directly
+		PrivateKeyFile        string        `json:"private_key_file" mapstructure:"private_key_file"`                     // PrivateKeyFile is a file with the hex encoded private key (instead of private_key)
 		TopicName             string        `json:"topic_name" mapstructure:"topic_name"`                                 // TopicName is the name of the topic to subscribe to
 		PeerDiscoveryInterval time.Duration `json:"peer_discovery_interval" mapstructure:"peer_discovery_interval"`       // PeerDiscoveryInterval is the interval in which we will refresh the peer table and check peers for missing messages
 	}
 
 	// RPCConfig is the configuration for the RPC client
 	RPCConfig struct {
-		Host     string `json:"host" mapstructure:"host"`         // Host is the RPC host
-		Password string `json:"password" mapstructure:"password"` // Password is the RPC password
-		User     string `json:"user" mapstructure:"user"`         // User is the RPC username
+		CookieFile   string        `json:"cookie_file" mapstructure:"cookie_file"`     // CookieFile is the node's cookie file (instead of user and password), read again when it changes
+		Host         string        `json:"host" mapstructure:"host"`                   // Host is the RPC host
+		Password     string        `json:"password" mapstructure:"password"`           // Password is the RPC password
+		PasswordFile string        `json:"password_file" mapstructure:"password_file"` // PasswordFile is a file with the RPC password (instead of password)
+		Timeout      time.Duration `json:"timeout" mapstructure:"timeout"`             // 30s
+		User         string        `json:"user" mapstructure:"user"`                   // User is the RPC username
+		UserFile     string        `json:"user_file" mapstructure:"user_file"`         // UserFile is a file with the RPC username (instead of user)
 	}
 
 	// Services is the global services
 	Services struct {
+		Chain      *ChainHeight              // Block height of the node
 		Datastore  datastore.ClientInterface // Datastore interface
 		Log        LoggerInterface           // Logger interface
 		Node       NodeInterface             // Node interface
@@ -126,9 +221,55 @@ type (
 
 	// WebServerConfig is a configuration for the web HTTP Server
 	WebServerConfig struct {
-		IdleTimeout  time.Duration `json:"idle_timeout" mapstructure:"idle_timeout"`   // 60s
-		Port         string        `json:"port" mapstructure:"port"`                   // 3000
-		ReadTimeout  time.Duration `json:"read_timeout" mapstructure:"read_timeout"`   // 15s
-		WriteTimeout time.Duration `json:"write_timeout" mapstructure:"write_timeout"` // 15s
+		AdminToken     string        `json:"admin_token" mapstructure:"admin_token"`           // Bearer token of the admin requests (empty: the admin requests are not served)
+		AdminTokenFile string        `json:"admin_token_file" mapstructure:"admin_token_file"` // File with the admin token (instead of admin_token)
+		CORS           CORSConfig    `json:"cors" mapstructure:"cors"`                         // CORS policy for browser clients
+		IdleTimeout    time.Duration `json:"idle_timeout" mapstructure:"idle_timeout"`         // 60s
+		Port           string        `json:"port" mapstructure:"port"`                         // 3000
+		ReadTimeout    time.Duration `json:"read_timeout" mapstructure:"read_timeout"`         // 15s
+		WriteTimeout   time.Duration `json:"write_timeout" mapstructure:"write_timeout"`       // 15s
+	}
+
+	// WebhookConfig is the configuration for the webhook delivery outbox
+	WebhookConfig struct {
+		DeliveryInterval time.Duration           `json:"delivery_interval" mapstructure:"delivery_interval"` // DeliveryInterval is how often the outbox is checked for due deliveries
+		Endpoints        []WebhookEndpointConfig `json:"endpoints" mapstructure:"endpoints"`                 // Endpoints are the webhook subscribers (in addition to alert_webhook_url)
+		InitialBackoff   time.Duration           `json:"initial_backoff" mapstructure:"initial_backoff"`     // InitialBackoff is the delay before the first retry, doubled on every retry after
+		MaxAttempts      uint32                  `json:"max_attempts" mapstructure:"max_attempts"`           // MaxAttempts is the number of attempts before a delivery is dead-lettered
+		MaxBackoff       time.Duration           `json:"max_backoff" mapstructure:"max_backoff"`             // MaxBackoff caps the delay between retries
+	}
+
+	// WebhookEndpointConfig is a webhook subscriber
+	WebhookEndpointConfig struct {
+		AlertTypes      []string           `json:"alert_types" mapstructure:"alert_types"`           // Alert types to send, by name or number (empty: all alert types)
+		Email           WebhookEmailConfig `json:"email" mapstructure:"email"`                       // Email addresses and SMTP credentials (format: email)
+		Format          string             `json:"format" mapstructure:"format"`                     // json (default), slack, teams or email
+		Headers         map[string]string  `json:"headers" mapstructure:"headers"`                   // Extra request headers (e.g. Authorization)
+		HeadersFile     string             `json:"headers_file" mapstructure:"headers_file"`         // File with the headers, one "Name: value" per line (instead of headers)
+		Name            string             `json:"name" mapstructure:"name"`                         // Unique name of the endpoint
+		PayloadTemplate string             `json:"payload_template" mapstructure:"payload_template"` // Go text/template for the request body (empty: rendered by the format)
+		Secret          string             `json:"secret" mapstructure:"secret"`                     // Secret for the HMAC-SHA256 signature header (empty: unsigned)
+		SecretFile      string             `json:"secret_file" mapstructure:"secret_file"`           // File with the secret (instead of secret)
+		Timeout         time.Duration      `json:"timeout" mapstructure:"timeout"`                   // 10s
+		URL             string             `json:"url" mapstructure:"url"`                           // https://hooks.example.com/alerts or smtp://mail.example.com:587
+	}
+
+	// WebhookEmailConfig is the SMTP configuration for an email endpoint
+	WebhookEmailConfig struct {
+		From         string   `json:"from" mapstructure:"from"`                   // alerts@example.com
+		Password     string   `json:"password" mapstructure:"password"`           // SMTP password (empty: no authentication)
+		PasswordFile string   `json:"password_file" mapstructure:"password_file"` // File with the SMTP password (instead of password)
+		To           []string `json:"to" mapstructure:"to"`                       // soc@example.com
+		Username     string   `json:"username" mapstructure:"username"`           // SMTP username (empty: no authentication)
+	}
+
+	// CORSConfig is the cross-origin resource sharing policy for the web HTTP Server
+	// An empty list of allowed origins means only same-origin requests are allowed
+	CORSConfig struct {
+		AllowCredentials bool          `json:"allow_credentials" mapstructure:"allow_credentials"` // Sends Access-Control-Allow-Credentials (never combined with a wildcard origin)
+		AllowedHeaders   []string      `json:"allowed_headers" mapstructure:"allowed_headers"`     // Content-Type
+		AllowedMethods   []string      `json:"allowed_methods" mapstructure:"allowed_methods"`     // GET, HEAD, OPTIONS
+		AllowedOrigins   []string      `json:"allowed_origins" mapstructure:"allowed_origins"`     // https://example.com or * for any origin
+		MaxAge           time.Duration `json:"max_age" mapstructure:"max_age"`                     // How long a preflight response can be cached
 	}
 )
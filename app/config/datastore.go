@@ -2,12 +2,22 @@ package config
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/bitcoin-sv/alert-system/app/kvstore"
 	"github.com/mrz1836/go-logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/mrz1836/go-datastore"
 )
 
+// mongoIndexer is a model with MongoDB indexes (created by the auto migration)
+type mongoIndexer interface {
+	GetModelTableName() string
+	GetMongoIndexes() []mongo.IndexModel
+}
+
 // loadDatastore will load an instance of Datastore into the dependencies
 func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error {
 
@@ -31,12 +41,7 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 	case datastore.MySQL, datastore.PostgreSQL:
 
 		// Set the pw if not set
-		if len(c.Datastore.Password) > 0 && len(c.Datastore.SQLRead.Password) == 0 {
-			c.Datastore.SQLRead.Password = c.Datastore.Password
-		}
-		if len(c.Datastore.Password) > 0 && len(c.Datastore.SQLWrite.Password) == 0 {
-			c.Datastore.SQLWrite.Password = c.Datastore.Password
-		}
+		c.Datastore.defaultPasswords()
 
 		// Create the read/write options
 		options = append(options, datastore.WithSQL(c.Datastore.Engine, []*datastore.SQLConfig{
@@ -81,7 +86,22 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 				SslMode:   c.Datastore.SQLRead.SslMode,
 			},
 		}))
-	case datastore.Empty, datastore.MongoDB:
+	case datastore.MongoDB:
+		if c.Datastore.Mongo == nil || len(c.Datastore.Mongo.URI) == 0 || len(c.Datastore.Mongo.DatabaseName) == 0 {
+			return ErrNoMongoConfig
+		}
+		options = append(options, datastore.WithMongo(&datastore.MongoDBConfig{
+			CommonConfig: datastore.CommonConfig{
+				Debug:       c.Datastore.Debug,
+				TablePrefix: c.Datastore.TablePrefix,
+			},
+			DatabaseName: c.Datastore.Mongo.DatabaseName,
+			Transactions: true, // The alert and its changes are saved together (see checkMongoTransactions)
+			URI:          c.Datastore.Mongo.URI,
+		}), datastore.WithCustomMongoIndexer(mongoIndexes(models)))
+	case kvstore.Engine:
+		return c.loadKVStore(ctx, models)
+	case datastore.Empty:
 		return ErrDatastoreUnsupported
 	default:
 		return ErrDatastoreUnsupported
@@ -94,6 +114,95 @@ func (c *Config) loadDatastore(ctx context.Context, models []interface{}) error
 
 	// Load datastore or return an error
 	var err error
-	c.Services.Datastore, err = datastore.NewClient(ctx, options...)
-	return err
+	if c.Services.Datastore, err = datastore.NewClient(ctx, options...); err != nil {
+		return err
+	}
+
+	// MongoDB must support transactions (the saves are not atomic otherwise)
+	if c.Datastore.Engine == datastore.MongoDB {
+		if err = checkMongoTransactions(ctx, c.Services.Datastore); err != nil {
+			_ = c.Services.Datastore.Close(ctx)
+			c.Services.Datastore = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// mongoHello is the part of the MongoDB hello response that tells if the deployment supports transactions
+type mongoHello struct {
+	Msg     string `bson:"msg"`     // "isdbgrid" for a sharded cluster (mongos)
+	SetName string `bson:"setName"` // The name of the replica set (empty: standalone)
+}
+
+// supportsTransactions returns true if the deployment is a replica set or a sharded cluster
+func (h *mongoHello) supportsTransactions() bool {
+	return len(h.SetName) > 0 || h.Msg == "isdbgrid"
+}
+
+// checkMongoTransactions will return ErrMongoNoTransactions if the MongoDB deployment does not support
+// transactions (a standalone server, transactions need a replica set or a sharded cluster)
+func checkMongoTransactions(ctx context.Context, ds datastore.ClientInterface) error {
+	var hello mongoHello
+	if err := ds.GetMongoCollection("").Database().RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return fmt.Errorf("failed to check the mongodb deployment: %w", err)
+	} else if !hello.supportsTransactions() {
+		return ErrMongoNoTransactions
+	}
+	return nil
+}
+
+// defaultPasswords will set the read and write passwords to the datastore password if they are not set
+func (d *DatastoreConfig) defaultPasswords() {
+	if len(d.Password) == 0 {
+		return
+	}
+	if d.SQLRead != nil && len(d.SQLRead.Password) == 0 {
+		d.SQLRead.Password = d.Password
+	}
+	if d.SQLWrite != nil && len(d.SQLWrite.Password) == 0 {
+		d.SQLWrite.Password = d.Password
+	}
+}
+
+// loadKVStore will load the embedded key-value datastore (a single data file) into the dependencies
+func (c *Config) loadKVStore(ctx context.Context, models []interface{}) error {
+	databasePath := kvstore.DefaultDatabasePath
+	if c.Datastore.KVStore != nil && len(c.Datastore.KVStore.DatabasePath) > 0 {
+		databasePath = c.Datastore.KVStore.DatabasePath
+	}
+
+	client, err := kvstore.NewClient(&kvstore.Config{
+		AutoMigrate:  c.Datastore.AutoMigrate,
+		DatabasePath: databasePath,
+		Debug:        c.Datastore.Debug,
+		TablePrefix:  c.Datastore.TablePrefix,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Create the buckets of the models if enabled
+	if c.Datastore.AutoMigrate && models != nil {
+		if err = client.AutoMigrateDatabase(ctx, models...); err != nil {
+			_ = client.Close(ctx)
+			return err
+		}
+	}
+
+	c.Services.Datastore = client
+	return nil
+}
+
+// mongoIndexes will return the MongoDB indexer for the given models (indexes by collection name)
+func mongoIndexes(models []interface{}) func() map[string][]mongo.IndexModel {
+	return func() map[string][]mongo.IndexModel {
+		indexes := make(map[string][]mongo.IndexModel)
+		for _, m := range models {
+			if indexer, ok := m.(mongoIndexer); ok {
+				indexes[indexer.GetModelTableName()] = append(indexes[indexer.GetModelTableName()], indexer.GetMongoIndexes()...)
+			}
+		}
+		return indexes
+	}
 }
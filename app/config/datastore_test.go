@@ -2,13 +2,31 @@ package config
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
+	"github.com/bitcoin-sv/alert-system/app/kvstore"
 	"github.com/mrz1836/go-datastore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
+// testIndexedModel is a model with MongoDB indexes
+type testIndexedModel struct{}
+
+// GetModelTableName will get the collection name of the model
+func (m *testIndexedModel) GetModelTableName() string {
+	return "tests"
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *testIndexedModel) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{{Keys: bson.D{{Key: "hash", Value: 1}}}}
+}
+
 // TestLoadDatastore tests the cases of loadDatastore
 func TestLoadDatastore(t *testing.T) {
 
@@ -26,6 +44,34 @@ func TestLoadDatastore(t *testing.T) {
 		assert.Equal(t, ErrDatastoreUnsupported, err)
 	})
 
+	t.Run("failure - mongo without a uri", func(t *testing.T) {
+		c := &Config{
+			Datastore: DatastoreConfig{
+				Engine: datastore.MongoDB,
+				Mongo:  &MongoConfig{MongoDBConfig: datastore.MongoDBConfig{DatabaseName: "alert_system"}},
+			},
+		}
+		err := c.loadDatastore(context.Background(), nil)
+		require.ErrorIs(t, err, ErrNoMongoConfig)
+	})
+
+	t.Run("success - kvstore", func(t *testing.T) {
+		c := &Config{
+			Datastore: DatastoreConfig{
+				AutoMigrate: true,
+				Engine:      kvstore.Engine,
+				KVStore:     &KVStoreConfig{DatabasePath: filepath.Join(t.TempDir(), "test.kv")},
+				TablePrefix: "test",
+			},
+		}
+		err := c.loadDatastore(context.Background(), []interface{}{&testIndexedModel{}})
+		require.NoError(t, err)
+		require.NotNil(t, c.Services.Datastore)
+		assert.Equal(t, kvstore.Engine, c.Services.Datastore.Engine())
+		assert.Equal(t, "test_tests", c.Services.Datastore.GetTableName("tests"))
+		require.NoError(t, c.Services.Datastore.Close(context.Background()))
+	})
+
 	t.Run("success - sqlite", func(t *testing.T) {
 
 		// Execute
@@ -53,3 +99,38 @@ func TestLoadDatastore(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestMongoIndexes tests the method mongoIndexes()
+func TestMongoIndexes(t *testing.T) {
+	t.Parallel()
+
+	indexes := mongoIndexes([]interface{}{&testIndexedModel{}, &struct{}{}})()
+	require.Len(t, indexes, 1)
+	require.Len(t, indexes["tests"], 1)
+	assert.Equal(t, bson.D{{Key: "hash", Value: 1}}, indexes["tests"][0].Keys)
+}
+
+// TestCheckMongoTransactions tests the method checkMongoTransactions()
+func TestCheckMongoTransactions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	// check will answer the hello command with the fields
+	check := func(mt *mtest.T, fields ...bson.E) error {
+		ds, err := datastore.NewClient(context.Background(), datastore.WithMongoConnection(mt.DB, "test"))
+		require.NoError(t, err)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(fields...))
+		return checkMongoTransactions(context.Background(), ds)
+	}
+
+	mt.Run("replica set", func(mt *mtest.T) {
+		require.NoError(t, check(mt, bson.E{Key: "setName", Value: "rs0"}))
+	})
+
+	mt.Run("sharded cluster", func(mt *mtest.T) {
+		require.NoError(t, check(mt, bson.E{Key: "msg", Value: "isdbgrid"}))
+	})
+
+	mt.Run("standalone server", func(mt *mtest.T) {
+		require.ErrorIs(t, check(mt, bson.E{Key: "isWritablePrimary", Value: true}), ErrMongoNoTransactions)
+	})
+}
@@ -6,9 +6,12 @@ import (
 
 // Configuration errors
 var (
+	ErrBlockNotFound        = errors.New("block not found on the node")
 	ErrDatastoreRequired    = errors.New("datastore is required and was not loaded")
 	ErrDatastoreUnsupported = errors.New("unsupported datastore engine")
 	ErrInvalidEnvironment   = errors.New("invalid environment")
+	ErrMongoNoTransactions  = errors.New("mongodb does not support transactions (use a replica set or a sharded cluster)")
+	ErrNoMongoConfig        = errors.New("no mongo uri or database_name defined")
 	ErrNoP2PIP              = errors.New("no p2p_ip defined")
 	ErrNoP2PPort            = errors.New("no p2p_port defined")
 	ErrNoRPCHost            = errors.New("no rpc_host defined")
@@ -16,4 +19,45 @@ var (
 	ErrNoRPCUser            = errors.New("no rpc_user defined")
 	ErrNoRPCConnections     = errors.New("no rpc connections configured")
 	ErrNoGenesisKeys        = errors.New("no genesis keys configured")
+	ErrNodeUnavailable      = errors.New("node is unavailable (circuit open after failed calls)")
+	ErrRPCCookieFile        = errors.New("rpc cookie file cannot be read")
+	ErrWebhookDuplicateName = errors.New("webhook endpoint name is not unique")
+	ErrWebhookEmailAddress  = errors.New("webhook email endpoint requires a from and a to address")
+	ErrWebhookInvalidFormat = errors.New("webhook endpoint format must be json, slack, teams or email")
+	ErrWebhookInvalidURL    = errors.New("webhook endpoint url must start with http:// or https:// (smtp:// for email)")
+	ErrWebhookNoName        = errors.New("webhook endpoint is missing a name")
+)
+
+// Configuration validation errors (see Validate)
+var (
+	ErrInvalidCORSOrigin = errors.New("must be * or a URL starting with http:// or https://")
+	ErrInvalidCookieFile = errors.New("set either the cookie_file or the user and password, not both")
+	ErrInvalidDHTMode    = errors.New("must be client or server (empty: automatic)")
+	ErrInvalidDuration   = errors.New("duration must not be negative")
+	ErrInvalidCount      = errors.New("must not be negative")
+	ErrInvalidGenesisKey = errors.New("must be a hex encoded public key")
+	ErrInvalidHost       = errors.New("must be an IPv4 address or a domain name")
+	ErrInvalidIP         = errors.New("must be an IPv4 address")
+	ErrInvalidLogLevel   = errors.New("must be debug, info, warn or error")
+	ErrInvalidMongoURI   = errors.New("must be a URL starting with mongodb:// or mongodb+srv://")
+	ErrInvalidMultiaddr  = errors.New("must be a multiaddr (e.g. /ip4/1.2.3.4/tcp/9906/p2p/<peer id>)")
+	ErrInvalidPort       = errors.New("must be a port number from 1 to 65535")
+	ErrInvalidPrivateKey = errors.New("must be a hex encoded private key")
+	ErrInvalidURL        = errors.New("must be a URL starting with http:// or https://")
+	ErrUnknownSetting    = errors.New("unknown setting")
+)
+
+// Secret reference errors (see resolveSecrets)
+var (
+	ErrSecretBothSet      = errors.New("set either the secret or its _file variant, not both")
+	ErrSecretEnvNotSet    = errors.New("secret references an environment variable that is not set")
+	ErrSecretFileNotFound = errors.New("secret file cannot be read")
+	ErrSecretHeadersFile  = errors.New("headers file has a line that is not a header")
+)
+
+// Bitcoin configuration errors (see loadBitcoinConfiguration)
+var (
+	ErrBitcoinConfNetwork    = errors.New("bitcoin.conf chain is not a known network (main, test, regtest or stn)")
+	ErrBitcoinConfNoPassword = errors.New("bitcoin.conf has rpcauth (the password is hashed), set the rpc_connections password")
+	ErrBitcoinConfParse      = errors.New("bitcoin.conf line is not valid")
 )
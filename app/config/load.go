@@ -1,19 +1,20 @@
 package config
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log"
-	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/mrz1836/go-datastore"
 	"github.com/spf13/viper"
 )
@@ -37,50 +38,26 @@ func isValidEnvironment(testEnv string) bool {
 // if testing is true, the node will be mocked
 func LoadDependencies(ctx context.Context, models []interface{}, isTesting bool) (_appConfig *Config, err error) {
 
-	// Load the config file
-	_appConfig, err = LoadConfigFile()
-	if err != nil {
+	// Load and validate the config file
+	if _appConfig, err = loadValidConfigFile(nil); err != nil {
 		return nil, err
 	}
+	_appConfig.reload.isTesting = isTesting
 
-	// Require at least one RPC connection
-	if len(_appConfig.RPCConnections) == 0 {
-		return nil, ErrNoRPCConnections
-	}
-
-	// Require list of genesis keys
-	if len(_appConfig.GenesisKeys) == 0 {
-		return nil, ErrNoGenesisKeys
-	}
-
-	// Ensure the P2P configuration is valid
-	if err = requireP2P(_appConfig); err != nil {
+	// Create the private key directory (not done when only checking the config)
+	if err = _appConfig.createPrivateKeyDirectory(); err != nil {
 		return nil, err
 	}
 
 	// Set the node config (either a real node or a mock node)
-	if !isTesting {
-		// todo support multiple nodes (this is an example)
-		for i := range _appConfig.RPCConnections {
-			_appConfig.Services.Node = NewNodeConfig(
-				_appConfig.RPCConnections[i].User,
-				_appConfig.RPCConnections[i].Password,
-				_appConfig.RPCConnections[i].Host,
-			)
-		}
-	} else {
-		for i := range _appConfig.RPCConnections {
-			_appConfig.Services.Node = NewNodeMock(
-				_appConfig.RPCConnections[i].User,
-				_appConfig.RPCConnections[i].Password,
-				_appConfig.RPCConnections[i].Host,
-			)
-		}
-	}
+	_appConfig.Services.Node = _appConfig.newNode()
 
 	// Load an HTTP client
 	_appConfig.Services.HTTPClient = http.DefaultClient
 
+	// The block height is followed from the node (see ChainHeight)
+	_appConfig.Services.Chain = &ChainHeight{}
+
 	// Load the datastore service
 	if err = _appConfig.loadDatastore(ctx, models); err != nil {
 		return nil, err
@@ -89,67 +66,148 @@ func LoadDependencies(ctx context.Context, models []interface{}, isTesting bool)
 	return
 }
 
-// requireP2P will ensure the P2P configuration is valid
-func requireP2P(_appConfig *Config) error {
+// loadValidConfigFile will load the config file and ensure the settings are valid
+// (the logger is used instead of creating a new one, if given)
+func loadValidConfigFile(logger LoggerInterface) (_appConfig *Config, err error) {
+
+	// Load the config file with the defaults
+	if _appConfig, err = loadConfigFileWithDefaults(logger); err != nil {
+		return nil, err
+	}
+
+	// Ensure all the settings are valid
+	if err = _appConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// loadConfigFileWithDefaults will load the config file, the logger and the defaults (not validated)
+func loadConfigFileWithDefaults(logger LoggerInterface) (_appConfig *Config, err error) {
+
+	// Load the config file
+	if _appConfig, err = readConfigFile(); err != nil {
+		return nil, err
+	}
+
+	// Load the logger service
+	if logger != nil {
+		_appConfig.Services.Log = logger
+	} else if err = _appConfig.loadLogger(); err != nil {
+		return nil, err
+	}
+	_appConfig.Services.Log.Debug("loaded configuration from: " + viper.ConfigFileUsed())
+
+	// Load the secrets from files and environment variables (the problems are returned by Validate)
+	_appConfig.secretProblems = _appConfig.resolveSecrets()
+
+	// Set the P2P defaults (and load the RPC connection from bitcoin.conf)
+	if err = _appConfig.setP2PDefaults(); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// CheckConfigFile will load the config file and environment variables and return all the problems
+// with the settings (as ValidationErrors), without loading any services
+func CheckConfigFile() error {
+	_, err := loadValidConfigFile(nil)
+	return err
+}
+
+// LoadEffectiveConfig will load the config file and environment variables (with the defaults) and return
+// the effective configuration, without validating it or loading any services
+func LoadEffectiveConfig() (*EffectiveConfig, error) {
+	c, err := loadConfigFileWithDefaults(nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Effective(), nil
+}
+
+// newNode will create the node pool from the RPC connections (mock nodes when testing)
+func (c *Config) newNode() NodeInterface {
+	return NewNodePool(c.NodeHealth, c.Services.Log, c.newNodes(c.RPCConnections)...)
+}
+
+// newNodes will create the node of every RPC connection (mock nodes when testing)
+func (c *Config) newNodes(connections []RPCConfig) []NodeInterface {
+	nodes := make([]NodeInterface, 0, len(connections))
+	for i := range connections {
+		if c.reload.isTesting {
+			nodes = append(nodes, NewNodeMock(connections[i].User, connections[i].Password, connections[i].Host))
+		} else {
+			nodes = append(nodes, newConnectionNode(connections[i]))
+		}
+	}
+	return nodes
+}
+
+// setP2PDefaults will set the missing P2P settings and load the bitcoin configuration (if specified)
+func (c *Config) setP2PDefaults() error {
 
 	// Set the P2P alert system protocol ID if it's missing
-	if len(_appConfig.P2P.AlertSystemProtocolID) == 0 {
-		_appConfig.P2P.AlertSystemProtocolID = DefaultAlertSystemProtocolID
+	if len(c.P2P.AlertSystemProtocolID) == 0 {
+		c.P2P.AlertSystemProtocolID = DefaultAlertSystemProtocolID
 	}
 
 	// Set the p2p alert system topic name if it's missing
-	if len(_appConfig.P2P.TopicName) == 0 {
-		_appConfig.P2P.TopicName = DefaultTopicName
+	if len(c.P2P.TopicName) == 0 {
+		c.P2P.TopicName = DefaultTopicName
 	}
 
 	// Load the private key path
-	// If not found, create a default one
-	if len(_appConfig.P2P.PrivateKeyPath) == 0 {
-		if err := _appConfig.createPrivateKeyDirectory(); err != nil {
+	// If not found, use the default one (the directory is created by LoadDependencies)
+	if len(c.P2P.PrivateKeyPath) == 0 {
+		path, err := defaultPrivateKeyPath()
+		if err != nil {
 			return err
 		}
+		c.P2P.PrivateKeyPath = path
 	}
 
 	// Load bitcoin configuration if specified
-	if len(_appConfig.BitcoinConfigPath) > 0 {
-		if err := _appConfig.loadBitcoinConfiguration(); err != nil {
+	if len(c.BitcoinConfigPath) > 0 {
+		if err := c.loadBitcoinConfiguration(); err != nil {
 			return err
 		}
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources["rpc_connections[0]"] = SourceBitcoinConf // The other connections are from the config
 	}
 
-	// Load the peer discovery interval
-	if _appConfig.P2P.PeerDiscoveryInterval <= 0 {
-		_appConfig.P2P.PeerDiscoveryInterval = DefaultPeerDiscoveryInterval
-	}
-
-	// Load the p2p ip (local, ip address or domain name)
-	// todo better validation of what is a valid IP, domain name or local address
-	if len(_appConfig.P2P.IP) < 5 {
-		return ErrNoP2PIP
-	}
-
-	// Load the p2p port ( >= XX)
-	if len(_appConfig.P2P.Port) < 2 {
-		return ErrNoP2PPort
+	// Load the peer discovery interval (a negative interval is invalid)
+	if c.P2P.PeerDiscoveryInterval == 0 {
+		c.P2P.PeerDiscoveryInterval = DefaultPeerDiscoveryInterval
 	}
 
 	return nil
 }
 
-// LoadConfigFile will load the config file and environment variables
-func LoadConfigFile() (_appConfig *Config, err error) {
+// LoadConfigFile will load the config file and environment variables (with the defaults and the secrets)
+// and ensure the settings are valid, loading only the logger service
+func LoadConfigFile() (*Config, error) {
+	return loadValidConfigFile(nil)
+}
+
+// readConfigFile will read the config file and environment variables and set the default values
+func readConfigFile() (_appConfig *Config, err error) {
 
 	// Start the configuration struct
 	_appConfig = &Config{
 		Datastore: DatastoreConfig{
 			SQLite:   &datastore.SQLiteConfig{},
-			SQLRead:  &datastore.SQLConfig{},
-			SQLWrite: &datastore.SQLConfig{},
+			SQLRead:  &SQLConfig{},
+			SQLWrite: &SQLConfig{},
 		},
 		P2P:            P2PConfig{},
 		Services:       Services{},
 		WebServer:      WebServerConfig{},
 		RPCConnections: make([]RPCConfig, 0),
+		reload:         newReloadState(),
 	}
 
 	// Check the environment we are running
@@ -185,8 +243,11 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 	viper.SetConfigType("json")
 
 	// Do we have a custom config file? (use this instead of the environment file)
+	fileSource := SourceEmbeddedFile
 	customConfigFileWithPath := os.Getenv(EnvironmentCustomFilePath)
 	if len(customConfigFileWithPath) > 0 {
+		fileSource = SourceCustomFile
+		_appConfig.configFile = customConfigFileWithPath
 		var b []byte
 
 		// Read the file
@@ -209,128 +270,128 @@ func LoadConfigFile() (_appConfig *Config, err error) {
 				if err = viper.ReadConfig(f); err != nil {
 					return nil, err
 				}
+				_appConfig.configFile = "envs/" + file.Name() + " (embedded)"
 			}
 		}
 	}
 
-	// Unmarshal into values struct
-	if err = viper.Unmarshal(&_appConfig); err != nil {
+	// Unmarshal into values struct (keeping the keys that are not settings, see Validate)
+	metadata := &mapstructure.Metadata{}
+	if err = viper.Unmarshal(&_appConfig, func(decoderConfig *mapstructure.DecoderConfig) {
+		decoderConfig.Metadata = metadata
+	}); err != nil {
 		err = fmt.Errorf("error loading viper values: %w", err)
 		return nil, err
 	}
+	_appConfig.unknownSettings = metadata.Unused
+	sort.Strings(_appConfig.unknownSettings)
 
-	// Load the logger service (ExtendedLogger meets the LoggerInterface)
-	writer := os.Stdout
-	if _appConfig.LogOutputFile != "" {
-		writer, err = os.OpenFile(_appConfig.LogOutputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
-		if err != nil {
-			return nil, err
+	// Keep where the settings were loaded from (see Effective)
+	_appConfig.sources = settingSources(viper.AllKeys(), fileSource, viper.InConfig)
+
+	// Set default alert processing interval if it doesn't exist
+	if _appConfig.AlertProcessingInterval == 0 {
+		_appConfig.AlertProcessingInterval = DefaultAlertProcessingInterval
+	}
+	if _appConfig.AlertVerificationInterval == 0 {
+		_appConfig.AlertVerificationInterval = DefaultAlertVerificationInterval
+	}
+	if _appConfig.ChainHeightInterval == 0 {
+		_appConfig.ChainHeightInterval = DefaultChainHeightInterval
+	}
+
+	// Set the default webhook delivery values if they don't exist
+	if _appConfig.Webhook.DeliveryInterval == 0 {
+		_appConfig.Webhook.DeliveryInterval = DefaultWebhookDeliveryInterval
+	}
+	if _appConfig.Webhook.InitialBackoff == 0 {
+		_appConfig.Webhook.InitialBackoff = DefaultWebhookInitialBackoff
+	}
+	if _appConfig.Webhook.MaxAttempts == 0 {
+		_appConfig.Webhook.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if _appConfig.Webhook.MaxBackoff == 0 {
+		_appConfig.Webhook.MaxBackoff = DefaultWebhookMaxBackoff
+	}
+	for i := range _appConfig.RPCConnections {
+		if _appConfig.RPCConnections[i].Timeout == 0 {
+			_appConfig.RPCConnections[i].Timeout = DefaultRPCTimeout
+		}
+	}
+	for i := range _appConfig.Webhook.Endpoints {
+		if _appConfig.Webhook.Endpoints[i].Timeout == 0 {
+			_appConfig.Webhook.Endpoints[i].Timeout = DefaultWebhookTimeout
 		}
 	}
 
-	logger := log.New(writer, "bitcoin-alert-system: ", log.LstdFlags)
-	_appConfig.Services.Log = &ExtendedLogger{
-		Logger:   logger,
-		writer:   writer,
-		logLevel: _appConfig.LogLevel,
+	// Set the default node health values if they don't exist
+	if _appConfig.NodeHealth.CircuitOpenTimeout == 0 {
+		_appConfig.NodeHealth.CircuitOpenTimeout = DefaultNodeCircuitOpenTimeout
+	}
+	if _appConfig.NodeHealth.FailureThreshold == 0 {
+		_appConfig.NodeHealth.FailureThreshold = DefaultNodeFailureThreshold
+	}
+	if _appConfig.NodeHealth.ProbeInterval == 0 {
+		_appConfig.NodeHealth.ProbeInterval = DefaultNodeProbeInterval
 	}
 
-	// Set default alert processing interval if it doesn't exist
-	if _appConfig.AlertProcessingInterval <= 0 {
-		_appConfig.AlertProcessingInterval = DefaultAlertProcessingInterval
+	// Set the default gRPC port if it doesn't exist
+	if len(_appConfig.GRPC.Port) == 0 {
+		_appConfig.GRPC.Port = DefaultGRPCPort
 	}
 
-	// Log the configuration that was detected and where it was loaded from
-	_appConfig.Services.Log.Debug("loaded configuration from: " + viper.ConfigFileUsed())
+	// Set the default CORS methods and headers if they don't exist (origins stay empty: same-origin only)
+	if len(_appConfig.WebServer.CORS.AllowedMethods) == 0 {
+		_appConfig.WebServer.CORS.AllowedMethods = DefaultCORSAllowedMethods
+	}
+	if len(_appConfig.WebServer.CORS.AllowedHeaders) == 0 {
+		_appConfig.WebServer.CORS.AllowedHeaders = DefaultCORSAllowedHeaders
+	}
 
 	return
 }
 
-// createPrivateKeyDirectory will create the private key directory
-func (c *Config) createPrivateKeyDirectory() error {
-	dirName, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to initialize p2p private key file: %w", err)
+// loadLogger will load the logger service (ExtendedLogger meets the LoggerInterface)
+func (c *Config) loadLogger() (err error) {
+	writer := os.Stdout
+	if c.LogOutputFile != "" {
+		writer, err = os.OpenFile(c.LogOutputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
 	}
-	if err = os.Mkdir(fmt.Sprintf("%s/%s", dirName, LocalPrivateKeyDirectory), 0750); err != nil && !errors.Is(err, os.ErrExist) {
-		return fmt.Errorf("failed to ensure %s dir exists: %w", LocalPrivateKeyDirectory, err)
+
+	logger := log.New(writer, "bitcoin-alert-system: ", log.LstdFlags)
+	c.Services.Log = &ExtendedLogger{
+		Logger:   logger,
+		writer:   writer,
+		logLevel: c.LogLevel,
 	}
-	c.P2P.PrivateKeyPath = fmt.Sprintf("%s/%s/%s", dirName, LocalPrivateKeyDirectory, LocalPrivateKeyDefault)
 	return nil
 }
 
-// loadBitcoinConfiguration will load the RPC configuration from bitcoin.conf
-func (c *Config) loadBitcoinConfiguration() error {
-	if len(c.BitcoinConfigPath) == 0 {
-		return nil
-	}
-	c.Services.Log.Infof("loading RPC configuration from %s", c.BitcoinConfigPath)
-	file, err := os.Open(c.BitcoinConfigPath)
+// defaultPrivateKeyPath will return the default private key path (in the home directory)
+func defaultPrivateKeyPath() (string, error) {
+	dirName, err := os.UserHomeDir()
 	if err != nil {
-		return err
-	}
-	scanner := bufio.NewScanner(file)
-	scanner.Split(splitFunc)
-	confValues := map[string]string{}
-	for scanner.Scan() {
-		kv := scanner.Text()
-		keyValue := strings.Split(kv, "=")
-		if len(keyValue) != 2 {
-			continue
-		}
-		confValues[keyValue[0]] = keyValue[1]
-	}
-	// Get the default host and ports in case they are not set
-	defaultHostPort := c.RPCConnections[0].Host
-	// Trim off http or https
-	defaultHostPortTrimmed := strings.TrimPrefix(defaultHostPort, "http://")
-	defaultHostPortTrimmed = strings.TrimPrefix(defaultHostPortTrimmed, "https://")
-	defaults := strings.Split(defaultHostPortTrimmed, ":")
-	host := confValues["rpcconnect"]
-	if host == "" {
-		c.Services.Log.Debugf("rpcconnect value not detected in bitcoin.conf")
-		host = defaults[0]
-	}
-	port := confValues["rpcport"]
-	if port == "" {
-		c.Services.Log.Debugf("rpcport value not detected in bitcoin.conf")
-		port = defaults[1]
-	}
-
-	user := confValues["rpcuser"]
-	if user == "" {
-		return fmt.Errorf("rpcuser missing from bitcoin.conf file")
-	}
-	pass := confValues["rpcpassword"]
-	if pass == "" {
-		return fmt.Errorf("rpcpassword missing from bitcoin.conf file")
-	}
-	c.RPCConnections = []RPCConfig{
-		{
-			Host:     fmt.Sprintf("http://%s", net.JoinHostPort(host, port)),
-			Password: pass,
-			User:     user,
-		},
+		return "", fmt.Errorf("failed to initialize p2p private key file: %w", err)
 	}
-
-	return file.Close()
+	return fmt.Sprintf("%s/%s/%s", dirName, LocalPrivateKeyDirectory, LocalPrivateKeyDefault), nil
 }
 
-func splitFunc(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if atEOF && len(data) == 0 {
-		return 0, nil, nil
+// createPrivateKeyDirectory will create the private key directory (only if the default private key path is used)
+func (c *Config) createPrivateKeyDirectory() error {
+	path, err := defaultPrivateKeyPath()
+	if err != nil {
+		return err
 	}
-
-	if atEOF {
-		return len(data), data, nil
+	if c.P2P.PrivateKeyPath != path {
+		return nil
 	}
-
-	//newline is the k-v pair delimiter
-	if i := strings.Index(string(data), "\n"); i >= 0 {
-		//skip the delimiter in advancing to the next pair
-		return i + 1, data[0:i], nil
+	if err = os.Mkdir(filepath.Dir(path), 0750); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to ensure %s dir exists: %w", LocalPrivateKeyDirectory, err)
 	}
-	return
+	return nil
 }
 
 // CloseAll will close all connections to all services
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -51,6 +52,22 @@ func TestLoadConfigFile(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid environment")
 	})
 
+	t.Run("invalid settings", func(t *testing.T) {
+		err := os.Setenv(EnvironmentKey, EnvironmentTest)
+		require.NoError(t, err)
+
+		err = os.Setenv("ALERT_SYSTEM_P2P__PORT", " ")
+		require.NoError(t, err)
+		defer func() {
+			_ = os.Unsetenv("ALERT_SYSTEM_P2P__PORT")
+		}()
+
+		var ac *Config
+		ac, err = LoadConfigFile()
+		require.Nil(t, ac)
+		require.ErrorIs(t, err, ErrNoP2PPort)
+	})
+
 	t.Run("missing rpc connections", func(t *testing.T) {
 		err := os.Setenv(EnvironmentKey, EnvironmentTest)
 		require.NoError(t, err)
@@ -85,7 +102,7 @@ func TestLoadConfigFile(t *testing.T) {
 		require.Nil(t, c)
 
 		require.Error(t, err)
-		assert.Equal(t, ErrNoP2PIP, err)
+		require.ErrorIs(t, err, ErrNoP2PIP)
 	})
 
 	t.Run("missing port", func(t *testing.T) {
@@ -104,7 +121,7 @@ func TestLoadConfigFile(t *testing.T) {
 		require.Nil(t, c)
 
 		require.Error(t, err)
-		assert.Equal(t, ErrNoP2PPort, err)
+		require.ErrorIs(t, err, ErrNoP2PPort)
 	})
 
 	t.Run("invalid custom file path for config", func(t *testing.T) {
@@ -192,6 +209,32 @@ func TestLoadConfigFile(t *testing.T) {
 	})
 }
 
+// TestConfig_PrivateKeyDirectory tests the default private key path and createPrivateKeyDirectory()
+func TestConfig_PrivateKeyDirectory(t *testing.T) {
+	t.Run("the defaults do not create the directory", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		c := &Config{}
+		require.NoError(t, c.setP2PDefaults())
+		assert.Equal(t, filepath.Join(home, LocalPrivateKeyDirectory, LocalPrivateKeyDefault), c.P2P.PrivateKeyPath)
+		assert.NoDirExists(t, filepath.Join(home, LocalPrivateKeyDirectory))
+
+		require.NoError(t, c.createPrivateKeyDirectory())
+		assert.DirExists(t, filepath.Join(home, LocalPrivateKeyDirectory))
+	})
+
+	t.Run("a custom private key path is not created", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		c := &Config{P2P: P2PConfig{PrivateKeyPath: filepath.Join(home, "keys", "key")}}
+		require.NoError(t, c.createPrivateKeyDirectory())
+		assert.NoDirExists(t, filepath.Join(home, LocalPrivateKeyDirectory))
+		assert.NoDirExists(t, filepath.Join(home, "keys"))
+	})
+}
+
 // TestIsValidEnvironment will test the method isValidEnvironment()
 func TestIsValidEnvironment(t *testing.T) {
 	t.Run("empty env", func(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 )
 
 // LoggerInterface is the interface for the logger
@@ -29,11 +30,17 @@ type LoggerInterface interface {
 	// GetLogLevel() gocore.logLevel
 }
 
+// LogLevelSetter is a logger that can change its logging level (used when the configuration is reloaded)
+type LogLevelSetter interface {
+	SetLogLevel(level string)
+}
+
 // ExtendedLogger is the extended logger to satisfy the LoggerInterface
 type ExtendedLogger struct {
 	*log.Logger
-	logLevel string
-	writer   *os.File
+	levelLock sync.RWMutex
+	logLevel  string
+	writer    *os.File
 }
 
 // CloseWriter close the log writer
@@ -48,7 +55,7 @@ func (es *ExtendedLogger) Printf(format string, v ...interface{}) {
 
 // Debugf will print debug messages to the console
 func (es *ExtendedLogger) Debugf(format string, v ...interface{}) {
-	if es.logLevel != "debug" {
+	if es.LogLevel() != "debug" {
 		return
 	}
 	es.Logger.Printf(fmt.Sprintf("\033[1;34m| DEBUG | %s\033[0m", format), v...)
@@ -56,7 +63,7 @@ func (es *ExtendedLogger) Debugf(format string, v ...interface{}) {
 
 // Debug will print debug messages to the console
 func (es *ExtendedLogger) Debug(v ...interface{}) {
-	if es.logLevel != "debug" {
+	if es.LogLevel() != "debug" {
 		return
 	}
 	es.Logger.Printf("%v", v...)
@@ -89,9 +96,18 @@ func (es *ExtendedLogger) Infof(format string, v ...interface{}) {
 
 // LogLevel returns the logging level
 func (es *ExtendedLogger) LogLevel() string {
+	es.levelLock.RLock()
+	defer es.levelLock.RUnlock()
 	return es.logLevel
 }
 
+// SetLogLevel will change the logging level
+func (es *ExtendedLogger) SetLogLevel(level string) {
+	es.levelLock.Lock()
+	es.logLevel = level
+	es.levelLock.Unlock()
+}
+
 // Warn will print warning messages to the console
 func (es *ExtendedLogger) Warn(v ...interface{}) {
 	es.Logger.Printf("%v", v...)
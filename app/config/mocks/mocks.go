@@ -3,6 +3,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/bsv-blockchain/go-bn/models"
 )
@@ -21,6 +22,13 @@ type Node struct {
 	UnbanPeerFunc                             func(ctx context.Context, peer string) error
 	AddToConsensusBlacklistFunc               func(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error)
 	AddToConfiscationTransactionWhitelistFunc func(ctx context.Context, tx []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error)
+	IsBlockInvalidFunc                        func(ctx context.Context, hash string) (bool, error)
+	IsConfiscationWhitelistedFunc             func(ctx context.Context, txID string) (bool, error)
+	IsFundBlacklistedFunc                     func(ctx context.Context, fund models.Fund) (bool, error)
+	IsPeerBannedFunc                          func(ctx context.Context, peer string) (bool, error)
+	BlockCountFunc                            func(ctx context.Context) (int64, error)
+	BlockHeightFunc                           func(ctx context.Context, hash string) (int64, error)
+	UptimeFunc                                func(ctx context.Context) (time.Duration, error)
 	// Add additional fields if needed to track calls or results
 }
 
@@ -87,3 +95,59 @@ func (n *Node) AddToConfiscationTransactionWhitelist(ctx context.Context, tx []m
 	}
 	return nil, nil
 }
+
+// IsBlockInvalid will call the IsBlockInvalidFunc if not nil, otherwise return false
+func (n *Node) IsBlockInvalid(ctx context.Context, hash string) (bool, error) {
+	if n.IsBlockInvalidFunc != nil {
+		return n.IsBlockInvalidFunc(ctx, hash)
+	}
+	return false, nil
+}
+
+// IsConfiscationWhitelisted will call the IsConfiscationWhitelistedFunc if not nil, otherwise return false
+func (n *Node) IsConfiscationWhitelisted(ctx context.Context, txID string) (bool, error) {
+	if n.IsConfiscationWhitelistedFunc != nil {
+		return n.IsConfiscationWhitelistedFunc(ctx, txID)
+	}
+	return false, nil
+}
+
+// IsFundBlacklisted will call the IsFundBlacklistedFunc if not nil, otherwise return false
+func (n *Node) IsFundBlacklisted(ctx context.Context, fund models.Fund) (bool, error) {
+	if n.IsFundBlacklistedFunc != nil {
+		return n.IsFundBlacklistedFunc(ctx, fund)
+	}
+	return false, nil
+}
+
+// IsPeerBanned will call the IsPeerBannedFunc if not nil, otherwise return false
+func (n *Node) IsPeerBanned(ctx context.Context, peer string) (bool, error) {
+	if n.IsPeerBannedFunc != nil {
+		return n.IsPeerBannedFunc(ctx, peer)
+	}
+	return false, nil
+}
+
+// BlockCount will call the BlockCountFunc if not nil, otherwise return 0
+func (n *Node) BlockCount(ctx context.Context) (int64, error) {
+	if n.BlockCountFunc != nil {
+		return n.BlockCountFunc(ctx)
+	}
+	return 0, nil
+}
+
+// BlockHeight will call the BlockHeightFunc if not nil, otherwise return 0
+func (n *Node) BlockHeight(ctx context.Context, hash string) (int64, error) {
+	if n.BlockHeightFunc != nil {
+		return n.BlockHeightFunc(ctx, hash)
+	}
+	return 0, nil
+}
+
+// Uptime will call the UptimeFunc if not nil, otherwise return 0
+func (n *Node) Uptime(ctx context.Context) (time.Duration, error) {
+	if n.UptimeFunc != nil {
+		return n.UptimeFunc(ctx)
+	}
+	return 0, nil
+}
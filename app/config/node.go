@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/bsv-blockchain/go-bn/models"
 
@@ -20,6 +22,13 @@ type NodeInterface interface {
 	UnbanPeer(ctx context.Context, peer string) error
 	AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error)
 	AddToConfiscationTransactionWhitelist(ctx context.Context, tx []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error)
+	IsBlockInvalid(ctx context.Context, hash string) (bool, error)
+	IsConfiscationWhitelisted(ctx context.Context, txID string) (bool, error)
+	IsFundBlacklisted(ctx context.Context, fund models.Fund) (bool, error)
+	IsPeerBanned(ctx context.Context, peer string) (bool, error)
+	BlockCount(ctx context.Context) (int64, error)
+	BlockHeight(ctx context.Context, hash string) (int64, error)
+	Uptime(ctx context.Context) (time.Duration, error)
 }
 
 // NewNodeConfig creates a new NodeConfig struct
@@ -28,6 +37,7 @@ func NewNodeConfig(user, pass, host string) NodeInterface {
 		RPCUser:     user,
 		RPCPassword: pass,
 		RPCHost:     host,
+		RPCTimeout:  DefaultRPCTimeout,
 	}
 }
 
@@ -40,14 +50,37 @@ func NewNodeMock(user, pass, host string) NodeInterface {
 	}
 }
 
+// NewCookieNodeConfig creates a new NodeConfig struct that reads the RPC user and password from the
+// node's cookie file (the cookie is read when needed, so the node does not have to be running yet)
+func NewCookieNodeConfig(cookieFile, host string) NodeInterface {
+	return &Node{
+		RPCCookieFile: cookieFile,
+		RPCHost:       host,
+		RPCTimeout:    DefaultRPCTimeout,
+	}
+}
+
+// newConnectionNode creates the node for the RPC connection (with a cookie file or a user and password)
+func newConnectionNode(connection RPCConfig) *Node {
+	return &Node{
+		RPCCookieFile: connection.CookieFile,
+		RPCHost:       connection.Host,
+		RPCPassword:   connection.Password,
+		RPCTimeout:    connection.Timeout,
+		RPCUser:       connection.User,
+	}
+}
+
 // GetRPCUser returns the RPC user
 func (n *Node) GetRPCUser() string {
-	return n.RPCUser
+	user, _, _ := n.credentials(false)
+	return user
 }
 
 // GetRPCPassword returns the RPC password
 func (n *Node) GetRPCPassword() string {
-	return n.RPCPassword
+	_, password, _ := n.credentials(false)
+	return password
 }
 
 // GetRPCHost returns the RPC host
@@ -55,38 +88,107 @@ func (n *Node) GetRPCHost() string {
 	return n.RPCHost
 }
 
+// credentials returns the RPC user and password, from the cookie file if there is one
+// (read it again if reload is true, or if it was not read yet)
+func (n *Node) credentials(reload bool) (user, password string, err error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	err = n.readCookie(reload)
+	return n.RPCUser, n.RPCPassword, err
+}
+
+// readCookie will read the RPC user and password from the cookie file (if reload is true, or if it was
+// not read yet), a new client is created when they changed (call while locked)
+func (n *Node) readCookie(reload bool) error {
+	if len(n.RPCCookieFile) == 0 || (!reload && len(n.RPCPassword) > 0) {
+		return nil
+	}
+	user, password, err := readCookieFile(n.RPCCookieFile)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRPCCookieFile, err.Error())
+	}
+	if user != n.RPCUser || password != n.RPCPassword {
+		n.RPCUser, n.RPCPassword, n.rpcClient = user, password, nil
+	}
+	return nil
+}
+
+// client returns the node client, which is kept for every call (connections are reused), and
+// only created again when the cookie changed
+func (n *Node) client(reloadCookie bool) (bn.NodeClient, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if err := n.readCookie(reloadCookie); err != nil {
+		return nil, err
+	}
+	if n.rpcClient == nil && n.RPCTimeout > 0 {
+		n.rpcClient = bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost), bn.WithTimeout(n.RPCTimeout))
+	} else if n.rpcClient == nil {
+		n.rpcClient = bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
+	}
+	return n.rpcClient, nil
+}
+
+// call will run the RPC call with the node client, and again if it fails and the cookie file changed
+// (the node writes a new cookie when it restarts, so the old one fails to authenticate)
+func (n *Node) call(fn func(c bn.NodeClient) error) error {
+	c, err := n.client(false)
+	if err != nil {
+		return err
+	}
+	if err = fn(c); err == nil || len(n.RPCCookieFile) == 0 {
+		return err
+	}
+	if newClient, cookieErr := n.client(true); cookieErr == nil && newClient != c {
+		return fn(newClient)
+	}
+	return err
+}
+
 // InvalidateBlock invalidates a block
 func (n *Node) InvalidateBlock(ctx context.Context, hash string) error {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.InvalidateBlock(ctx, hash)
+	return n.call(func(c bn.NodeClient) error {
+		return c.InvalidateBlock(ctx, hash)
+	})
 }
 
 // BanPeer bans a peer
 func (n *Node) BanPeer(ctx context.Context, peer string) error {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.SetBan(ctx, peer, bn.BanActionAdd, nil)
+	return n.call(func(c bn.NodeClient) error {
+		return c.SetBan(ctx, peer, bn.BanActionAdd, nil)
+	})
 }
 
 // BestBlockHash gets the best block hash
-func (n *Node) BestBlockHash(ctx context.Context) (string, error) {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.BestBlockHash(ctx)
+func (n *Node) BestBlockHash(ctx context.Context) (hash string, err error) {
+	err = n.call(func(c bn.NodeClient) (callErr error) {
+		hash, callErr = c.BestBlockHash(ctx)
+		return
+	})
+	return
 }
 
 // UnbanPeer unbans a peer
 func (n *Node) UnbanPeer(ctx context.Context, peer string) error {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.SetBan(ctx, peer, bn.BanActionRemove, nil)
+	return n.call(func(c bn.NodeClient) error {
+		return c.SetBan(ctx, peer, bn.BanActionRemove, nil)
+	})
 }
 
 // AddToConsensusBlacklist adds frozen utxos to blacklist
-func (n *Node) AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (*models.AddToConsensusBlacklistResponse, error) {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.AddToConsensusBlacklist(ctx, funds)
+func (n *Node) AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (response *models.AddToConsensusBlacklistResponse, err error) {
+	err = n.call(func(c bn.NodeClient) (callErr error) {
+		response, callErr = c.AddToConsensusBlacklist(ctx, funds)
+		return
+	})
+	return
 }
 
 // AddToConfiscationTransactionWhitelist adds confiscation transactions to the whitelist
-func (n *Node) AddToConfiscationTransactionWhitelist(ctx context.Context, tx []models.ConfiscationTransactionDetails) (*models.AddToConfiscationTransactionWhitelistResponse, error) {
-	c := bn.NewNodeClient(bn.WithCreds(n.RPCUser, n.RPCPassword), bn.WithHost(n.RPCHost))
-	return c.AddToConfiscationTransactionWhitelist(ctx, tx)
+func (n *Node) AddToConfiscationTransactionWhitelist(ctx context.Context, tx []models.ConfiscationTransactionDetails) (response *models.AddToConfiscationTransactionWhitelistResponse, err error) {
+	err = n.call(func(c bn.NodeClient) (callErr error) {
+		response, callErr = c.AddToConfiscationTransactionWhitelist(ctx, tx)
+		return
+	})
+	return
 }
@@ -0,0 +1,445 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// Circuit states of a node (see NodeHealth)
+const (
+	CircuitClosed   = "closed"    // The calls are made to the node
+	CircuitHalfOpen = "half_open" // One call is made to check if the node is back
+	CircuitOpen     = "open"      // The calls fail without calling the node (ErrNodeUnavailable)
+)
+
+type (
+
+	// NodePool is the node of every RPC connection, with a liveness probe and a circuit breaker per node
+	// (a node that is down fails fast instead of waiting for the RPC timeout on every call)
+	//
+	// The actions are performed on every node, and the queries of the chain (BestBlockHash, BlockCount and
+	// Uptime) are answered by the first node that answers. The nodes are replaced when the configuration is
+	// reloaded (see Replace)
+	NodePool struct {
+		lock     sync.RWMutex     // Held (read) by the calls to the nodes, held (write) to replace the nodes
+		log      LoggerInterface  // Logs the circuit changes (optional)
+		nodes    []*poolNode      // The nodes (in the order of the RPC connections)
+		settings NodeHealthConfig // Failure threshold and circuit open timeout
+	}
+
+	// poolNode is a node in the pool with its health
+	poolNode struct {
+		health      NodeHealth    // Current health
+		invalidated *int64        // The lowest height of the blocks invalidated since the last restart check (nil: none)
+		lock        sync.Mutex    // Guards the health and the restart state
+		node        NodeInterface // The node
+		openedAt    time.Time     // When the circuit was opened
+		restart     nodeRestart   // The state of the node at the last restart check
+		restarted   bool          // A restart was detected and the alerts are not applied again yet
+	}
+
+	// nodeRestart is what the node reported at a restart check
+	nodeRestart struct {
+		checkedAt time.Time     // When the node was checked (zero: never)
+		hash      string        // The best block hash
+		height    int64         // The height of the best chain
+		uptime    time.Duration // How long the node was running
+	}
+
+	// NodeHealth is the health of a node (from the last call or probe)
+	NodeHealth struct {
+		Circuit             string    `json:"circuit"`              // closed, half_open or open
+		ConsecutiveFailures int       `json:"consecutive_failures"` // Failed calls since the last success
+		Healthy             bool      `json:"healthy"`              // The circuit is closed
+		Host                string    `json:"host"`                 // The RPC host
+		LastCheck           time.Time `json:"last_check"`           // The last call or probe (zero: none yet)
+		LastError           string    `json:"last_error,omitempty"` // The error of the last failed call
+		LastSuccess         time.Time `json:"last_success"`         // The last successful call or probe (zero: none yet)
+		Restarts            int       `json:"restarts"`             // Restarts (or resets) of the node that were detected
+	}
+)
+
+// NewNodePool creates a pool of the nodes (their circuits start closed)
+func NewNodePool(settings NodeHealthConfig, log LoggerInterface, nodes ...NodeInterface) *NodePool {
+	return &NodePool{log: log, nodes: newPoolNodes(nodes), settings: settings}
+}
+
+// newPoolNodes will create the pool nodes of the nodes (their circuits start closed)
+func newPoolNodes(nodes []NodeInterface) []*poolNode {
+	poolNodes := make([]*poolNode, 0, len(nodes))
+	for _, node := range nodes {
+		poolNodes = append(poolNodes, &poolNode{
+			health: NodeHealth{Circuit: CircuitClosed, Healthy: true, Host: node.GetRPCHost()},
+			node:   node,
+		})
+	}
+	return poolNodes
+}
+
+// Replace will replace the nodes and the settings of the pool (after a reload of the configuration), once the
+// calls in progress to the current nodes are done: the new nodes start with closed circuits and no restart
+// state
+func (p *NodePool) Replace(settings NodeHealthConfig, nodes ...NodeInterface) {
+	poolNodes := newPoolNodes(nodes)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.nodes = poolNodes
+	p.settings = settings
+}
+
+// Nodes returns the nodes in the pool (without the circuit breakers)
+func (p *NodePool) Nodes() []NodeInterface {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	nodes := make([]NodeInterface, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		nodes = append(nodes, n.node)
+	}
+	return nodes
+}
+
+// Health returns the health of every node
+func (p *NodePool) Health() []NodeHealth {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	health := make([]NodeHealth, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		n.lock.Lock()
+		health = append(health, n.health)
+		n.lock.Unlock()
+	}
+	return health
+}
+
+// Healthy returns true if the circuit of at least one node is closed
+func (p *NodePool) Healthy() bool {
+	for _, health := range p.Health() {
+		if health.Healthy {
+			return true
+		}
+	}
+	return false
+}
+
+// Probe will check the liveness of every node (at the same time) with a best block hash call, even if
+// its circuit is open: a node that answers is closed again, and a node that does not is opened
+func (p *NodePool) Probe(ctx context.Context) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	var wg sync.WaitGroup
+	for _, n := range p.nodes {
+		wg.Add(1)
+		go func(n *poolNode) {
+			defer wg.Done()
+			_, err := n.node.BestBlockHash(ctx)
+			p.record(n, err)
+		}(n)
+	}
+	wg.Wait()
+}
+
+// allow returns ErrNodeUnavailable if the circuit of the node is open (after the open timeout, one call
+// is allowed to check if the node is back)
+func (p *NodePool) allow(n *poolNode) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	switch n.health.Circuit {
+	case CircuitOpen:
+		if time.Since(n.openedAt) < p.settings.CircuitOpenTimeout {
+			return ErrNodeUnavailable
+		}
+		n.health.Circuit = CircuitHalfOpen
+	case CircuitHalfOpen: // The call to check the node is not done yet
+		return ErrNodeUnavailable
+	}
+	return nil
+}
+
+// record will update the health of the node with the result of a call, and open the circuit after
+// too many failures in a row (or if the call to check the node failed)
+func (p *NodePool) record(n *poolNode, err error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	previous := n.health.Circuit
+	n.health.LastCheck = time.Now().UTC()
+	if err == nil {
+		n.health.Circuit = CircuitClosed
+		n.health.ConsecutiveFailures = 0
+		n.health.LastError = ""
+		n.health.LastSuccess = n.health.LastCheck
+	} else {
+		n.health.ConsecutiveFailures++
+		n.health.LastError = err.Error()
+		if previous != CircuitClosed || n.health.ConsecutiveFailures >= p.settings.FailureThreshold {
+			n.health.Circuit = CircuitOpen
+			n.openedAt = n.health.LastCheck
+		}
+	}
+	n.health.Healthy = n.health.Circuit == CircuitClosed
+
+	// Log when the node goes down or comes back
+	if p.log == nil || (previous == CircuitOpen) == (n.health.Circuit == CircuitOpen) {
+		return
+	} else if n.health.Circuit == CircuitOpen {
+		p.log.Errorf("node %s is unavailable after %d failed calls: %s", n.health.Host, n.health.ConsecutiveFailures, n.health.LastError)
+	} else {
+		p.log.Infof("node %s is available again", n.health.Host)
+	}
+}
+
+// call will make the call to the node through its circuit breaker (call with the pool locked for reading)
+func (p *NodePool) call(n *poolNode, fn func(node NodeInterface) error) error {
+	if err := p.allow(n); err != nil {
+		return fmt.Errorf("node %s: %w", n.health.Host, err)
+	}
+	err := fn(n.node)
+	p.record(n, err)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", n.health.Host, err)
+	}
+	return nil
+}
+
+// find returns the node in the pool, nil if it is not in the pool (call with the pool locked for reading)
+func (p *NodePool) find(node NodeInterface) *poolNode {
+	for _, n := range p.nodes {
+		if n.node == node {
+			return n
+		}
+	}
+	return nil
+}
+
+// On will make the call to one node of the pool (see Nodes) through its circuit breaker
+func (p *NodePool) On(node NodeInterface, fn func(node NodeInterface) error) error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	n := p.find(node)
+	if n == nil {
+		return ErrNoRPCConnections
+	}
+	return p.call(n, fn)
+}
+
+// each will make the call to every node, and return the errors of the nodes that failed
+func (p *NodePool) each(fn func(node NodeInterface) error) error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.nodes) == 0 {
+		return ErrNoRPCConnections
+	}
+	errs := make([]error, 0)
+	for _, n := range p.nodes {
+		if err := p.call(n, fn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// all returns true if the check is true on every node (the errors of the nodes that failed)
+func (p *NodePool) all(check func(node NodeInterface) (bool, error)) (bool, error) {
+	result := true
+	err := p.each(func(node NodeInterface) error {
+		ok, checkErr := check(node)
+		result = result && ok
+		return checkErr
+	})
+	return result && err == nil, err
+}
+
+// GetRPCHost returns the RPC host of the first node
+func (p *NodePool) GetRPCHost() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.nodes) == 0 {
+		return ""
+	}
+	return p.nodes[0].node.GetRPCHost()
+}
+
+// GetRPCPassword returns the RPC password of the first node
+func (p *NodePool) GetRPCPassword() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.nodes) == 0 {
+		return ""
+	}
+	return p.nodes[0].node.GetRPCPassword()
+}
+
+// GetRPCUser returns the RPC user of the first node
+func (p *NodePool) GetRPCUser() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.nodes) == 0 {
+		return ""
+	}
+	return p.nodes[0].node.GetRPCUser()
+}
+
+// first will make the call to the nodes (in order) until one succeeds, and return the errors of the nodes
+// that failed if none did
+func (p *NodePool) first(fn func(node NodeInterface) error) error {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if len(p.nodes) == 0 {
+		return ErrNoRPCConnections
+	}
+	errs := make([]error, 0)
+	for _, n := range p.nodes {
+		err := p.call(n, fn)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// BestBlockHash gets the best block hash from the first node that answers
+func (p *NodePool) BestBlockHash(ctx context.Context) (hash string, err error) {
+	err = p.first(func(node NodeInterface) (callErr error) {
+		hash, callErr = node.BestBlockHash(ctx)
+		return
+	})
+	return
+}
+
+// BlockCount gets the height of the best chain from the first node that answers
+func (p *NodePool) BlockCount(ctx context.Context) (count int64, err error) {
+	err = p.first(func(node NodeInterface) (callErr error) {
+		count, callErr = node.BlockCount(ctx)
+		return
+	})
+	return
+}
+
+// BlockHeight gets the height of the block from the first node that answers (ErrBlockNotFound if that node
+// does not have the block)
+func (p *NodePool) BlockHeight(ctx context.Context, hash string) (height int64, err error) {
+	notFound := false
+	err = p.first(func(node NodeInterface) (callErr error) {
+		if height, callErr = node.BlockHeight(ctx, hash); errors.Is(callErr, ErrBlockNotFound) {
+			notFound, callErr = true, nil // The node answered
+		}
+		return
+	})
+	if err == nil && notFound {
+		return 0, ErrBlockNotFound
+	}
+	return
+}
+
+// Uptime gets the uptime of the first node that answers
+func (p *NodePool) Uptime(ctx context.Context) (uptime time.Duration, err error) {
+	err = p.first(func(node NodeInterface) (callErr error) {
+		uptime, callErr = node.Uptime(ctx)
+		return
+	})
+	return
+}
+
+// InvalidateBlock invalidates a block on every node (the height of the block is kept for the restart check,
+// the best chain of the node goes back below it)
+func (p *NodePool) InvalidateBlock(ctx context.Context, hash string) error {
+	return p.each(func(node NodeInterface) error {
+		if err := node.InvalidateBlock(ctx, hash); err != nil {
+			return err
+		}
+		p.blockInvalidated(ctx, node, hash)
+		return nil
+	})
+}
+
+// blockInvalidated will keep the lowest height of the blocks invalidated on the node since the last restart
+// check, 0 if the height is unknown, so the height of the node is not used by the next check (call with the
+// pool locked for reading)
+func (p *NodePool) blockInvalidated(ctx context.Context, node NodeInterface, hash string) {
+	n := p.find(node)
+	if n == nil {
+		return
+	}
+	height, err := node.BlockHeight(ctx, hash)
+	if err != nil {
+		height = 0
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.invalidated == nil || height < *n.invalidated {
+		n.invalidated = &height
+	}
+}
+
+// BanPeer bans a peer on every node
+func (p *NodePool) BanPeer(ctx context.Context, peer string) error {
+	return p.each(func(node NodeInterface) error {
+		return node.BanPeer(ctx, peer)
+	})
+}
+
+// UnbanPeer unbans a peer on every node
+func (p *NodePool) UnbanPeer(ctx context.Context, peer string) error {
+	return p.each(func(node NodeInterface) error {
+		return node.UnbanPeer(ctx, peer)
+	})
+}
+
+// AddToConsensusBlacklist adds frozen utxos to the blacklist of every node (the response is from the first
+// node that succeeded)
+func (p *NodePool) AddToConsensusBlacklist(ctx context.Context, funds []models.Fund) (response *models.AddToConsensusBlacklistResponse, err error) {
+	err = p.each(func(node NodeInterface) error {
+		res, callErr := node.AddToConsensusBlacklist(ctx, funds)
+		if callErr == nil && response == nil {
+			response = res
+		}
+		return callErr
+	})
+	return
+}
+
+// AddToConfiscationTransactionWhitelist adds confiscation transactions to the whitelist of every node (the
+// response is from the first node that succeeded)
+func (p *NodePool) AddToConfiscationTransactionWhitelist(ctx context.Context, tx []models.ConfiscationTransactionDetails) (response *models.AddToConfiscationTransactionWhitelistResponse, err error) {
+	err = p.each(func(node NodeInterface) error {
+		res, callErr := node.AddToConfiscationTransactionWhitelist(ctx, tx)
+		if callErr == nil && response == nil {
+			response = res
+		}
+		return callErr
+	})
+	return
+}
+
+// IsBlockInvalid returns true if the block is invalid on every node
+func (p *NodePool) IsBlockInvalid(ctx context.Context, hash string) (bool, error) {
+	return p.all(func(node NodeInterface) (bool, error) {
+		return node.IsBlockInvalid(ctx, hash)
+	})
+}
+
+// IsConfiscationWhitelisted returns true if the confiscation transaction is whitelisted on every node
+func (p *NodePool) IsConfiscationWhitelisted(ctx context.Context, txID string) (bool, error) {
+	return p.all(func(node NodeInterface) (bool, error) {
+		return node.IsConfiscationWhitelisted(ctx, txID)
+	})
+}
+
+// IsFundBlacklisted returns true if the fund is in the consensus blacklist of every node
+func (p *NodePool) IsFundBlacklisted(ctx context.Context, fund models.Fund) (bool, error) {
+	return p.all(func(node NodeInterface) (bool, error) {
+		return node.IsFundBlacklisted(ctx, fund)
+	})
+}
+
+// IsPeerBanned returns true if the peer is banned on every node
+func (p *NodePool) IsPeerBanned(ctx context.Context, peer string) (bool, error) {
+	return p.all(func(node NodeInterface) (bool, error) {
+		return node.IsPeerBanned(ctx, peer)
+	})
+}
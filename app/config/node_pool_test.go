@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errTestNodeDown is returned by a test node that is down
+var errTestNodeDown = errors.New("connection refused")
+
+// newTestNodePool will create a pool of mock nodes (failure threshold of 2)
+func newTestNodePool(nodes ...*mocks.Node) *NodePool {
+	poolNodes := make([]NodeInterface, 0, len(nodes))
+	for _, node := range nodes {
+		poolNodes = append(poolNodes, node)
+	}
+	return NewNodePool(NodeHealthConfig{
+		CircuitOpenTimeout: time.Minute,
+		FailureThreshold:   2,
+		ProbeInterval:      time.Minute,
+	}, nil, poolNodes...)
+}
+
+// TestNodePool_circuit tests the circuit breaker of the NodePool
+func TestNodePool_circuit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("the circuit opens after the failure threshold", func(t *testing.T) {
+		calls := 0
+		p := newTestNodePool(&mocks.Node{RPCHost: "http://node:8332", BanPeerFunc: func(context.Context, string) error {
+			calls++
+			return errTestNodeDown
+		}})
+
+		require.ErrorIs(t, p.BanPeer(ctx, "1.2.3.4"), errTestNodeDown)
+		assert.True(t, p.Healthy())
+		require.ErrorIs(t, p.BanPeer(ctx, "1.2.3.4"), errTestNodeDown)
+		assert.False(t, p.Healthy())
+
+		// The node is not called while the circuit is open
+		err := p.BanPeer(ctx, "1.2.3.4")
+		require.ErrorIs(t, err, ErrNodeUnavailable)
+		assert.Contains(t, err.Error(), "http://node:8332")
+		assert.Equal(t, 2, calls)
+
+		health := p.Health()
+		require.Len(t, health, 1)
+		assert.Equal(t, CircuitOpen, health[0].Circuit)
+		assert.Equal(t, 2, health[0].ConsecutiveFailures)
+		assert.Equal(t, errTestNodeDown.Error(), health[0].LastError)
+		assert.True(t, health[0].LastSuccess.IsZero())
+	})
+
+	t.Run("one call is tried after the open timeout", func(t *testing.T) {
+		var err error
+		p := newTestNodePool(&mocks.Node{BanPeerFunc: func(context.Context, string) error {
+			return err
+		}})
+		err = errTestNodeDown
+		_ = p.BanPeer(ctx, "1.2.3.4")
+		_ = p.BanPeer(ctx, "1.2.3.4")
+		require.False(t, p.Healthy())
+
+		// Still down: opened again
+		p.nodes[0].openedAt = time.Now().Add(-2 * time.Minute)
+		require.ErrorIs(t, p.BanPeer(ctx, "1.2.3.4"), errTestNodeDown)
+		require.ErrorIs(t, p.BanPeer(ctx, "1.2.3.4"), ErrNodeUnavailable)
+
+		// Back: closed
+		p.nodes[0].openedAt = time.Now().Add(-2 * time.Minute)
+		err = nil
+		require.NoError(t, p.BanPeer(ctx, "1.2.3.4"))
+		assert.True(t, p.Healthy())
+		assert.Equal(t, 0, p.Health()[0].ConsecutiveFailures)
+		assert.False(t, p.Health()[0].LastSuccess.IsZero())
+	})
+
+	t.Run("the probe closes the circuit", func(t *testing.T) {
+		var err error
+		p := newTestNodePool(&mocks.Node{
+			BanPeerFunc:       func(context.Context, string) error { return errTestNodeDown },
+			BestBlockHashFunc: func(context.Context) (string, error) { return "hash", err },
+		})
+		_ = p.BanPeer(ctx, "1.2.3.4")
+		_ = p.BanPeer(ctx, "1.2.3.4")
+		require.False(t, p.Healthy())
+
+		p.Probe(ctx)
+		assert.True(t, p.Healthy())
+
+		// A failed probe counts as a failure
+		err = errTestNodeDown
+		p.Probe(ctx)
+		p.Probe(ctx)
+		assert.False(t, p.Healthy())
+	})
+
+	t.Run("a call on one node goes through its circuit", func(t *testing.T) {
+		node := &mocks.Node{}
+		p := newTestNodePool(node)
+		fail := func(NodeInterface) error { return errTestNodeDown }
+		require.ErrorIs(t, p.On(node, fail), errTestNodeDown)
+		require.ErrorIs(t, p.On(node, fail), errTestNodeDown)
+		require.ErrorIs(t, p.On(node, fail), ErrNodeUnavailable)
+		assert.Equal(t, 2, p.Health()[0].ConsecutiveFailures)
+
+		// Not a node of the pool
+		require.ErrorIs(t, p.On(&mocks.Node{}, fail), ErrNoRPCConnections)
+	})
+}
+
+// TestNodePool_nodes tests the calls to multiple nodes of the NodePool
+func TestNodePool_nodes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("actions are performed on every node", func(t *testing.T) {
+		var banned []string
+		ban := func(host string, err error) func(context.Context, string) error {
+			return func(context.Context, string) error {
+				banned = append(banned, host)
+				return err
+			}
+		}
+		p := newTestNodePool(
+			&mocks.Node{RPCHost: "http://first:8332", BanPeerFunc: ban("first", nil)},
+			&mocks.Node{RPCHost: "http://second:8332", BanPeerFunc: ban("second", errTestNodeDown)},
+			&mocks.Node{RPCHost: "http://third:8332", BanPeerFunc: ban("third", nil)},
+		)
+		err := p.BanPeer(ctx, "1.2.3.4")
+		require.ErrorIs(t, err, errTestNodeDown)
+		assert.Contains(t, err.Error(), "http://second:8332")
+		assert.NotContains(t, err.Error(), "http://first:8332")
+		assert.Equal(t, []string{"first", "second", "third"}, banned)
+		assert.Len(t, p.Nodes(), 3)
+		assert.Equal(t, "http://first:8332", p.GetRPCHost())
+	})
+
+	t.Run("best block hash from the first node that answers", func(t *testing.T) {
+		p := newTestNodePool(
+			&mocks.Node{BestBlockHashFunc: func(context.Context) (string, error) { return "", errTestNodeDown }},
+			&mocks.Node{BestBlockHashFunc: func(context.Context) (string, error) { return "second", nil }},
+		)
+		hash, err := p.BestBlockHash(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "second", hash)
+	})
+
+	t.Run("no nodes", func(t *testing.T) {
+		p := newTestNodePool()
+		require.ErrorIs(t, p.BanPeer(ctx, "1.2.3.4"), ErrNoRPCConnections)
+		_, err := p.BestBlockHash(ctx)
+		require.ErrorIs(t, err, ErrNoRPCConnections)
+		assert.False(t, p.Healthy())
+		assert.Empty(t, p.GetRPCHost())
+	})
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// restartUptimeTolerance is how much less uptime than expected (from the previous check) a node can report
+// without being considered restarted (the uptime is in seconds, and the calls take time)
+const restartUptimeTolerance = 10 * time.Second
+
+// CheckRestarts will query the uptime and the best chain of every available node, and return the nodes that
+// were restarted or reset whose alerts are not applied again yet
+//
+// A node was restarted if its uptime went back. A node was reset (e.g. started with a new data directory)
+// if its height went back further than the blocks invalidated by the alerts explain, and it does not have the
+// best block of the previous check anymore (after a reorg, the node still has the block on a fork).
+//
+// The first check of a node only records its state, and a node stays restarted until Reconciled is called
+// (so the alerts are applied again on the next check if applying them failed)
+func (p *NodePool) CheckRestarts(ctx context.Context) []NodeInterface {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	restarted := make([]NodeInterface, 0)
+	for _, n := range p.nodes {
+		n.lock.Lock()
+		previous := n.restart
+		n.lock.Unlock()
+
+		var current nodeRestart
+		previousKnown := true
+		if err := p.call(n, func(node NodeInterface) (callErr error) {
+			if current.uptime, callErr = node.Uptime(ctx); callErr != nil {
+				return
+			} else if current.height, callErr = node.BlockCount(ctx); callErr != nil {
+				return
+			} else if current.hash, callErr = node.BestBlockHash(ctx); callErr != nil {
+				return
+			}
+
+			// The height went back: check the best block of the previous check is still on the node
+			if current.height < previous.height && len(previous.hash) > 0 {
+				if _, callErr = node.BlockHeight(ctx, previous.hash); errors.Is(callErr, ErrBlockNotFound) {
+					previousKnown, callErr = false, nil
+				}
+			}
+			return
+		}); err != nil {
+			continue
+		}
+		current.checkedAt = time.Now().UTC()
+		if p.detectRestart(n, current, previousKnown) {
+			restarted = append(restarted, n.node)
+		}
+	}
+	return restarted
+}
+
+// detectRestart will record the state of the node, and return true if the node is restarted (now or before,
+// and not reconciled yet)
+func (p *NodePool) detectRestart(n *poolNode, current nodeRestart, previousKnown bool) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	previous := n.restart
+	n.restart = current
+
+	// The best chain goes back below an invalidated block (to its parent)
+	lowest := previous.height
+	if n.invalidated != nil && *n.invalidated-1 < lowest {
+		lowest = *n.invalidated - 1
+	}
+	n.invalidated = nil
+	if previous.checkedAt.IsZero() {
+		return n.restarted
+	}
+
+	expectedUptime := previous.uptime + current.checkedAt.Sub(previous.checkedAt)
+	switch {
+	case current.uptime < expectedUptime-restartUptimeTolerance:
+		p.logRestart("node %s was restarted (uptime %s, expected %s), applying the alerts again", n.health.Host, current.uptime, expectedUptime.Round(time.Second))
+	case current.height < lowest && !previousKnown:
+		p.logRestart("node %s was reset (block height %d, was %d), applying the alerts again", n.health.Host, current.height, previous.height)
+	default:
+		return n.restarted
+	}
+	n.health.Restarts++
+	n.restarted = true
+	return true
+}
+
+// logRestart will log the restart of a node (if the pool has a logger)
+func (p *NodePool) logRestart(msg string, args ...interface{}) {
+	if p.log != nil {
+		p.log.Infof(msg, args...)
+	}
+}
+
+// Reconciled marks the restart of the node as handled (the alerts were applied to it again)
+func (p *NodePool) Reconciled(node NodeInterface) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if n := p.find(node); n != nil {
+		n.lock.Lock()
+		n.restarted = false
+		n.lock.Unlock()
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodePool_CheckRestarts tests the restart detection of the NodePool
+func TestNodePool_CheckRestarts(t *testing.T) {
+	ctx := context.Background()
+
+	// newNode returns a node reporting the uptime and height, whose best block is "<chain>-<height>" (the
+	// blocks of the chain up to the height are known, a reset node only knows the blocks of its new chain)
+	chain := "main"
+	newNode := func(uptime *time.Duration, height *int64) *mocks.Node {
+		return &mocks.Node{
+			RPCHost:           "http://node:8332",
+			BestBlockHashFunc: func(context.Context) (string, error) { return fmt.Sprintf("%s-%d", chain, *height), nil },
+			BlockCountFunc:    func(context.Context) (int64, error) { return *height, nil },
+			BlockHeightFunc: func(_ context.Context, hash string) (int64, error) {
+				var blockChain string
+				var blockHeight int64
+				if _, err := fmt.Sscanf(strings.Replace(hash, "-", " ", 1), "%s %d", &blockChain, &blockHeight); err != nil || blockChain != chain {
+					return 0, ErrBlockNotFound
+				}
+				return blockHeight, nil
+			},
+			UptimeFunc: func(context.Context) (time.Duration, error) { return *uptime, nil },
+		}
+	}
+
+	t.Run("the first check only records the state", func(t *testing.T) {
+		uptime, height := time.Second, int64(0)
+		p := newTestNodePool(newNode(&uptime, &height))
+		assert.Empty(t, p.CheckRestarts(ctx))
+		assert.Empty(t, p.CheckRestarts(ctx))
+	})
+
+	t.Run("the uptime went back", func(t *testing.T) {
+		uptime, height := time.Hour, int64(100)
+		p := newTestNodePool(newNode(&uptime, &height))
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		uptime = time.Minute
+		restarted := p.CheckRestarts(ctx)
+		require.Len(t, restarted, 1)
+		assert.Equal(t, 1, p.Health()[0].Restarts)
+
+		// Restarted until reconciled
+		uptime += time.Second
+		require.Len(t, p.CheckRestarts(ctx), 1)
+		p.Reconciled(restarted[0])
+		assert.Empty(t, p.CheckRestarts(ctx))
+		assert.Equal(t, 1, p.Health()[0].Restarts)
+	})
+
+	t.Run("restarted for longer than the previous uptime", func(t *testing.T) {
+		uptime, height := time.Minute, int64(100)
+		p := newTestNodePool(newNode(&uptime, &height))
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		// An hour later, the node was restarted 5 minutes ago
+		p.nodes[0].restart.checkedAt = p.nodes[0].restart.checkedAt.Add(-time.Hour)
+		uptime = 5 * time.Minute
+		assert.Len(t, p.CheckRestarts(ctx), 1)
+	})
+
+	t.Run("the node was reset", func(t *testing.T) {
+		uptime, height := time.Hour, int64(100)
+		p := newTestNodePool(newNode(&uptime, &height))
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		height = 101
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		// A new data directory: the node does not know the previous best block anymore
+		chain, height = "reset", 0
+		defer func() { chain = "main" }()
+		assert.Len(t, p.CheckRestarts(ctx), 1)
+		assert.Equal(t, 1, p.Health()[0].Restarts)
+	})
+
+	t.Run("a block is invalidated and no restart is reported", func(t *testing.T) {
+		uptime, height := time.Hour, int64(100)
+		node := newNode(&uptime, &height)
+		p := newTestNodePool(node)
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		// The best chain goes back to the parent of the invalidated block (on a new chain for the test, so
+		// only the invalidation explains the lower height)
+		require.NoError(t, p.InvalidateBlock(ctx, "main-90"))
+		chain, height = "invalidated", 89
+		defer func() { chain = "main" }()
+		assert.Empty(t, p.CheckRestarts(ctx))
+		assert.Equal(t, 0, p.Health()[0].Restarts)
+
+		// The invalidation only explains the next check
+		chain, height = "reset", 0
+		assert.Len(t, p.CheckRestarts(ctx), 1)
+	})
+
+	t.Run("a reorg to a shorter chain is not a restart", func(t *testing.T) {
+		uptime, height := time.Hour, int64(100)
+		p := newTestNodePool(newNode(&uptime, &height))
+		require.Empty(t, p.CheckRestarts(ctx))
+
+		// The previous best block is still known by the node (on a fork)
+		height = 98
+		assert.Empty(t, p.CheckRestarts(ctx))
+		assert.Equal(t, 0, p.Health()[0].Restarts)
+	})
+
+	t.Run("an unavailable node is not checked", func(t *testing.T) {
+		p := newTestNodePool(&mocks.Node{UptimeFunc: func(context.Context) (time.Duration, error) {
+			return 0, errTestNodeDown
+		}})
+		assert.Empty(t, p.CheckRestarts(ctx))
+		assert.Equal(t, 1, p.Health()[0].ConsecutiveFailures)
+	})
+}
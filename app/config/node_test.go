@@ -1,9 +1,12 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestNewNodeConfig creates a new NodeConfig struct
@@ -39,3 +42,37 @@ func TestNewNodeMock(t *testing.T) {
 		assert.Equal(t, "host", val)
 	})
 }
+
+// TestNewCookieNodeConfig creates a new NodeConfig struct with a cookie file
+func TestNewCookieNodeConfig(t *testing.T) {
+	t.Run("credentials from the cookie file", func(t *testing.T) {
+		cookieFile := filepath.Join(t.TempDir(), ".cookie")
+		node := NewCookieNodeConfig(cookieFile, "http://localhost:8332")
+		assert.Equal(t, "http://localhost:8332", node.GetRPCHost())
+
+		// The node is not running yet
+		_, _, err := node.(*Node).credentials(false)
+		require.ErrorIs(t, err, ErrRPCCookieFile)
+		assert.Empty(t, node.GetRPCPassword())
+
+		// The cookie is read once it exists
+		require.NoError(t, os.WriteFile(cookieFile, []byte("__cookie__:first\n"), 0o600))
+		assert.Equal(t, bitcoinCookieUser, node.GetRPCUser())
+		assert.Equal(t, "first", node.GetRPCPassword())
+
+		// The node restarted: the cookie is only read again when reloading (after a failed call)
+		require.NoError(t, os.WriteFile(cookieFile, []byte("__cookie__:second\n"), 0o600))
+		assert.Equal(t, "first", node.GetRPCPassword())
+		user, password, err := node.(*Node).credentials(true)
+		require.NoError(t, err)
+		assert.Equal(t, bitcoinCookieUser, user)
+		assert.Equal(t, "second", password)
+		assert.Equal(t, "second", node.GetRPCPassword())
+
+		// The node stopped: the last credentials are kept
+		require.NoError(t, os.Remove(cookieFile))
+		_, password, err = node.(*Node).credentials(true)
+		require.ErrorIs(t, err, ErrRPCCookieFile)
+		assert.Equal(t, "second", password)
+	})
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bsv-blockchain/go-bn"
+	"github.com/bsv-blockchain/go-bn/models"
+)
+
+// rpcErrorBlockNotFound is the error code of the node for an unknown block (RPC_INVALID_ADDRESS_OR_KEY)
+const rpcErrorBlockNotFound = -5
+
+type (
+
+	// rpcError is an error returned by the node for a query
+	rpcError struct {
+		Code    int
+		Message string
+		Method  string
+	}
+
+	// rpcRequest is a JSON-RPC request to the node
+	rpcRequest struct {
+		ID      string        `json:"id"`
+		JSONRPC string        `json:"jsonrpc"`
+		Method  string        `json:"method"`
+		Params  []interface{} `json:"params"`
+	}
+
+	// rpcResponse is a JSON-RPC response from the node
+	rpcResponse struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		Result json.RawMessage `json:"result"`
+	}
+
+	// chainTip is a tip of the getchaintips response
+	chainTip struct {
+		BranchLen int    `json:"branchlen"`
+		Hash      string `json:"hash"`
+		Status    string `json:"status"` // active, invalid, headers-only, valid-headers or valid-fork
+	}
+
+	// blockHeader is the getblockheader response
+	blockHeader struct {
+		Hash              string `json:"hash"`
+		Height            int64  `json:"height"`
+		PreviousBlockHash string `json:"previousblockhash"`
+	}
+
+	// bannedSubnet is an entry of the listbanned response
+	bannedSubnet struct {
+		Address string `json:"address"` // 1.2.3.4/32
+	}
+
+	// blacklistedFunds is the queryBlacklistedFunds response
+	blacklistedFunds struct {
+		Funds []struct {
+			models.Fund
+			Blacklist []string `json:"blacklist"` // policy, consensus
+		} `json:"funds"`
+	}
+
+	// confiscationWhitelist is the queryConfiscationTxidWhitelist response
+	confiscationWhitelist struct {
+		ConfiscationTxs []struct {
+			ConfiscationTx struct {
+				TxID string `json:"txId"`
+			} `json:"confiscationTx"`
+		} `json:"confiscationTxs"`
+	}
+)
+
+// query will make the JSON-RPC call to the node and decode the result (the calls that the node client
+// does not have, with the same credentials and timeout)
+func (n *Node) query(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	return n.call(func(_ bn.NodeClient) error {
+		user, password, err := n.credentials(false)
+		if err != nil {
+			return err
+		}
+		if params == nil {
+			params = []interface{}{}
+		}
+		var body []byte
+		if body, err = json.Marshal(rpcRequest{ID: method, JSONRPC: "1.0", Method: method, Params: params}); err != nil {
+			return err
+		}
+		var req *http.Request
+		if req, err = http.NewRequestWithContext(ctx, http.MethodPost, n.RPCHost, bytes.NewReader(body)); err != nil {
+			return err
+		}
+		req.SetBasicAuth(user, password)
+		req.Header.Set("Content-Type", "application/json")
+
+		var res *http.Response
+		if res, err = n.httpClient().Do(req); err != nil {
+			return err
+		}
+		defer func() {
+			_ = res.Body.Close()
+		}()
+
+		// The node answers errors with a JSON-RPC error (and a 500 or 404), but authentication errors without a body
+		var response rpcResponse
+		if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+			return fmt.Errorf("%s failed with status %d: %w", method, res.StatusCode, err)
+		} else if response.Error != nil {
+			return &rpcError{Code: response.Error.Code, Message: response.Error.Message, Method: method}
+		}
+		return json.Unmarshal(response.Result, result)
+	})
+}
+
+// Error returns the method and the error of the node
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s failed: %s (%d)", e.Method, e.Message, e.Code)
+}
+
+// httpClient returns the HTTP client for the queries (created on the first query, with the RPC timeout)
+func (n *Node) httpClient() *http.Client {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.queryClient == nil {
+		n.queryClient = &http.Client{Timeout: n.RPCTimeout}
+	}
+	return n.queryClient
+}
+
+// IsBlockInvalid returns true if the block is invalid on the node: it is on the branch of an invalid chain tip
+// (getchaintips, and getblockheader to walk the branch)
+func (n *Node) IsBlockInvalid(ctx context.Context, hash string) (bool, error) {
+	var tips []chainTip
+	if err := n.query(ctx, "getchaintips", &tips); err != nil {
+		return false, err
+	}
+	for _, tip := range tips {
+		if tip.Status != "invalid" {
+			continue
+		}
+		blockHash := tip.Hash
+		for i := 0; i < tip.BranchLen && len(blockHash) > 0; i++ {
+			if blockHash == hash {
+				return true, nil
+			}
+			var header blockHeader
+			if err := n.query(ctx, "getblockheader", &header, blockHash, true); err != nil {
+				return false, err
+			}
+			blockHash = header.PreviousBlockHash
+		}
+	}
+	return false, nil
+}
+
+// IsPeerBanned returns true if the peer (an IP address, optionally with a port, or a subnet) is banned on
+// the node (listbanned)
+func (n *Node) IsPeerBanned(ctx context.Context, peer string) (bool, error) {
+	var banned []bannedSubnet
+	if err := n.query(ctx, "listbanned", &banned); err != nil {
+		return false, err
+	}
+	host := peer
+	if splitHost, _, err := net.SplitHostPort(peer); err == nil {
+		host = splitHost
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	for _, subnet := range banned {
+		if subnet.Address == peer || subnet.Address == host {
+			return true, nil
+		}
+		if _, ipNet, err := net.ParseCIDR(subnet.Address); err == nil && ip != nil && ipNet.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsFundBlacklisted returns true if the fund is in the consensus blacklist of the node with the same
+// enforcement heights (queryBlacklistedFunds)
+func (n *Node) IsFundBlacklisted(ctx context.Context, fund models.Fund) (bool, error) {
+	var blacklisted blacklistedFunds
+	if err := n.query(ctx, "queryBlacklistedFunds", &blacklisted); err != nil {
+		return false, err
+	}
+	for _, f := range blacklisted.Funds {
+		if f.TxOut == fund.TxOut && contains(f.Blacklist, "consensus") {
+			return reflect.DeepEqual(f.EnforceAtHeight, fund.EnforceAtHeight), nil
+		}
+	}
+	return false, nil
+}
+
+// IsConfiscationWhitelisted returns true if the confiscation transaction is whitelisted on the node
+// (queryConfiscationTxidWhitelist)
+func (n *Node) IsConfiscationWhitelisted(ctx context.Context, txID string) (bool, error) {
+	var whitelist confiscationWhitelist
+	if err := n.query(ctx, "queryConfiscationTxidWhitelist", &whitelist, false); err != nil {
+		return false, err
+	}
+	for _, tx := range whitelist.ConfiscationTxs {
+		if tx.ConfiscationTx.TxID == txID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// BlockCount returns the height of the best chain of the node (getblockcount)
+func (n *Node) BlockCount(ctx context.Context) (int64, error) {
+	var count int64
+	if err := n.query(ctx, "getblockcount", &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Uptime returns how long the node has been running (uptime)
+func (n *Node) Uptime(ctx context.Context) (time.Duration, error) {
+	var seconds int64
+	if err := n.query(ctx, "uptime", &seconds); err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// BlockHeight returns the height of the block (getblockheader), or ErrBlockNotFound if the node does not
+// have the block
+func (n *Node) BlockHeight(ctx context.Context, hash string) (int64, error) {
+	var header blockHeader
+	if err := n.query(ctx, "getblockheader", &header, hash, true); err != nil {
+		var nodeErr *rpcError
+		if errors.As(err, &nodeErr) && nodeErr.Code == rpcErrorBlockNotFound {
+			return 0, ErrBlockNotFound
+		}
+		return 0, err
+	}
+	return header.Height, nil
+}
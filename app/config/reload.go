@@ -0,0 +1,208 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// reloadableSettings are the settings applied by Reload() without a restart (by name, nested with a period)
+var reloadableSettings = map[string]bool{
+	"alert_processing_interval":        true,
+	"alert_verification_interval":      true,
+	"alert_webhook_url":                true,
+	"bitcoin_config_path":              true, // Only used to load the RPC connections
+	"chain_height_interval":            true,
+	"log_level":                        true,
+	"node_health.circuit_open_timeout": true,
+	"node_health.failure_threshold":    true,
+	"node_health.probe_interval":       true,
+	"p2p.peer_discovery_interval":      true,
+	"rpc_connections":                  true,
+	"webhook.endpoints":                true,
+}
+
+// reloadState is the state for reloading the configuration
+type reloadState struct {
+	isTesting bool                               // Use a mock node (set by LoadDependencies)
+	lock      sync.Mutex                         // Only one reload at a time
+	reloaded  chan struct{}                      // Closed (and replaced) after every reload
+	settings  atomic.Pointer[ReloadableSettings] // The settings of the last reload (nil: not reloaded yet)
+}
+
+// ReloadableSettings are the settings that Reload changes while running (see Config.Reloadable)
+//
+// The settings are never changed after they are created (Reload replaces them), so they can be read while
+// the configuration is reloaded.
+type ReloadableSettings struct {
+	AlertProcessingInterval   time.Duration           // See Config.AlertProcessingInterval
+	AlertVerificationInterval time.Duration           // See Config.AlertVerificationInterval
+	AlertWebhookURL           string                  // See Config.AlertWebhookURL
+	BitcoinConfigPath         string                  // See Config.BitcoinConfigPath
+	ChainHeightInterval       time.Duration           // See Config.ChainHeightInterval
+	LogLevel                  string                  // See Config.LogLevel
+	NodeHealth                NodeHealthConfig        // See Config.NodeHealth
+	PeerDiscoveryInterval     time.Duration           // See P2PConfig.PeerDiscoveryInterval
+	RPCConnections            []RPCConfig             // See Config.RPCConnections
+	WebhookEndpoints          []WebhookEndpointConfig // See WebhookConfig.Endpoints
+}
+
+// Reloadable returns the current reloadable settings: the settings of the last reload, or the settings of
+// the configuration if it was not reloaded
+//
+// Read the reloadable settings with Reloadable while the configuration can be reloaded (Reload does not
+// change the fields of the configuration).
+func (c *Config) Reloadable() *ReloadableSettings {
+	if c.reload != nil {
+		if settings := c.reload.settings.Load(); settings != nil {
+			return settings
+		}
+	}
+	return &ReloadableSettings{
+		AlertProcessingInterval:   c.AlertProcessingInterval,
+		AlertVerificationInterval: c.AlertVerificationInterval,
+		AlertWebhookURL:           c.AlertWebhookURL,
+		BitcoinConfigPath:         c.BitcoinConfigPath,
+		ChainHeightInterval:       c.ChainHeightInterval,
+		LogLevel:                  c.LogLevel,
+		NodeHealth:                c.NodeHealth,
+		PeerDiscoveryInterval:     c.P2P.PeerDiscoveryInterval,
+		RPCConnections:            c.RPCConnections,
+		WebhookEndpoints:          c.Webhook.Endpoints,
+	}
+}
+
+// current returns a copy of the configuration with the current reloadable settings (see Reloadable)
+func (c *Config) current() Config {
+	current := *c
+	settings := c.Reloadable()
+	current.AlertProcessingInterval = settings.AlertProcessingInterval
+	current.AlertVerificationInterval = settings.AlertVerificationInterval
+	current.AlertWebhookURL = settings.AlertWebhookURL
+	current.BitcoinConfigPath = settings.BitcoinConfigPath
+	current.ChainHeightInterval = settings.ChainHeightInterval
+	current.LogLevel = settings.LogLevel
+	current.NodeHealth = settings.NodeHealth
+	current.P2P.PeerDiscoveryInterval = settings.PeerDiscoveryInterval
+	current.RPCConnections = settings.RPCConnections
+	current.Webhook.Endpoints = settings.WebhookEndpoints
+	return current
+}
+
+// newReloadState will create the reload state
+func newReloadState() *reloadState {
+	return &reloadState{reloaded: make(chan struct{})}
+}
+
+// ReloadResult is the result of reloading the configuration
+type ReloadResult struct {
+	Applied         []string // Changed settings that were applied
+	RequiresRestart []string // Changed settings that only take effect after a restart
+}
+
+// Reload will load the config file and environment variables again and apply the settings that are
+// safe to change while running: log level, webhook endpoints, RPC connections, node health checks, peer
+// discovery interval, alert processing interval and chain height interval
+//
+// The applied settings replace the settings returned by Reloadable (the fields of the configuration keep the
+// values that were loaded at startup). The nodes of the node pool are replaced once the calls in progress
+// are done.
+//
+// The new configuration is validated (also by validate, if given) before anything is changed. If it is
+// invalid, the error is returned and the running configuration is unchanged. Changes to the other
+// settings are returned as requiring a restart (they are not applied).
+func (c *Config) Reload(validate func(*Config) error) (*ReloadResult, error) {
+	if c.reload == nil {
+		c.reload = newReloadState()
+	}
+	c.reload.lock.Lock()
+	defer c.reload.lock.Unlock()
+
+	// Load and validate the new configuration
+	newConfig, err := loadValidConfigFile(c.Services.Log)
+	if err != nil {
+		return nil, err
+	}
+	newConfig.reload.isTesting = c.reload.isTesting
+	newConfig.Datastore.defaultPasswords()
+	if validate != nil {
+		if err = validate(newConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	// Find the changes
+	current := c.current()
+	result := &ReloadResult{}
+	for _, setting := range changedSettings("", reflect.ValueOf(current), reflect.ValueOf(*newConfig)) {
+		if reloadableSettings[setting] {
+			result.Applied = append(result.Applied, setting)
+		} else {
+			result.RequiresRestart = append(result.RequiresRestart, setting)
+		}
+	}
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	// Apply the changes
+	settings := newConfig.Reloadable()
+	if pool, ok := c.Services.Node.(*NodePool); ok &&
+		(!reflect.DeepEqual(current.RPCConnections, settings.RPCConnections) || current.NodeHealth != settings.NodeHealth) {
+		pool.Replace(settings.NodeHealth, c.newNodes(settings.RPCConnections)...)
+	}
+	c.reload.settings.Store(settings)
+	if setter, ok := c.Services.Log.(LogLevelSetter); ok {
+		setter.SetLogLevel(settings.LogLevel)
+	}
+
+	// Keep where the applied settings were loaded from (see Effective)
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	for _, setting := range result.Applied {
+		if source, ok := newConfig.sources[setting]; ok {
+			c.sources[setting] = source
+		} else {
+			delete(c.sources, setting)
+		}
+	}
+	// Notify the listeners (the crons reset their intervals)
+	close(c.reload.reloaded)
+	c.reload.reloaded = make(chan struct{})
+
+	return result, nil
+}
+
+// Reloaded returns a channel that is closed when the configuration is reloaded
+// (get the channel again after it is closed, to wait for the next reload)
+func (c *Config) Reloaded() <-chan struct{} {
+	if c.reload == nil {
+		return nil
+	}
+	c.reload.lock.Lock()
+	defer c.reload.lock.Unlock()
+	return c.reload.reloaded
+}
+
+// changedSettings will return the names of the settings (json names) that are different,
+// the nested structs are compared by field
+func changedSettings(prefix string, oldValue, newValue reflect.Value) (changed []string) {
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if !field.IsExported() || name == "-" || len(name) == 0 {
+			continue
+		}
+		name = prefix + name
+
+		if field.Type.Kind() == reflect.Struct && field.Type.NumField() > 0 && field.Type.PkgPath() == oldValue.Type().PkgPath() {
+			changed = append(changed, changedSettings(name+".", oldValue.Field(i), newValue.Field(i))...)
+		} else if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errTestInvalid is returned by the test validation
+var errTestInvalid = errors.New("invalid")
+
+// writeTestConfigFile will write the test environment config (with the changes) to a custom config file
+func writeTestConfigFile(t *testing.T, path string, changes map[string]interface{}) {
+	b, err := envDir.ReadFile("envs/test.json")
+	require.NoError(t, err)
+
+	var values map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &values))
+	for key, value := range changes {
+		values[key] = value
+	}
+
+	b, err = json.Marshal(values)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+}
+
+// newTestReloadConfig will load the config from a custom config file (with a mock node)
+func newTestReloadConfig(t *testing.T) (*Config, string) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeTestConfigFile(t, path, nil)
+	t.Setenv(EnvironmentKey, EnvironmentTest)
+	t.Setenv(EnvironmentCustomFilePath, path)
+
+	c, err := loadValidConfigFile(nil)
+	require.NoError(t, err)
+	c.reload.isTesting = true
+	c.Services.Node = c.newNode()
+	return c, path
+}
+
+// TestConfig_Reload tests the method Reload()
+func TestConfig_Reload(t *testing.T) {
+
+	t.Run("nothing changed", func(t *testing.T) {
+		c, _ := newTestReloadConfig(t)
+		result, err := c.Reload(nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.Applied)
+		assert.Empty(t, result.RequiresRestart)
+	})
+
+	t.Run("safe changes are applied", func(t *testing.T) {
+		c, path := newTestReloadConfig(t)
+		node := c.Services.Node
+		reloaded := c.Reloaded()
+
+		writeTestConfigFile(t, path, map[string]interface{}{
+			"alert_processing_interval":   "1m",
+			"alert_verification_interval": "1h",
+			"log_level":                   "debug",
+			"node_health":                 map[string]string{"probe_interval": "1m"},
+			"p2p": map[string]interface{}{
+				"ip":                      "192.168.1.1",
+				"port":                    "8000",
+				"peer_discovery_interval": "2m",
+				"private_key_path":        "/path/to/private/key",
+			},
+			"rpc_connections": []map[string]string{{"user": "new", "password": "new", "host": "http://localhost:18332"}},
+			"web_server":      map[string]string{"port": "4000"},
+		})
+		result, err := c.Reload(nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"alert_processing_interval", "alert_verification_interval", "log_level", "node_health.probe_interval",
+			"p2p.peer_discovery_interval", "rpc_connections",
+		}, result.Applied)
+		assert.Contains(t, result.RequiresRestart, "web_server.port")
+
+		// Applied (the nodes of the pool are replaced)
+		settings := c.Reloadable()
+		assert.Equal(t, time.Minute, settings.AlertProcessingInterval)
+		assert.Equal(t, time.Hour, settings.AlertVerificationInterval)
+		assert.Equal(t, "debug", c.Services.Log.LogLevel())
+		assert.Equal(t, time.Minute, settings.NodeHealth.ProbeInterval)
+		assert.Equal(t, 2*time.Minute, settings.PeerDiscoveryInterval)
+		assert.Equal(t, "http://localhost:18332", settings.RPCConnections[0].Host)
+		assert.Same(t, node, c.Services.Node)
+		assert.Equal(t, "http://localhost:18332", c.Services.Node.GetRPCHost())
+
+		// The fields keep the values loaded at startup
+		assert.Equal(t, "http://localhost:8333", c.RPCConnections[0].Host)
+
+		// Not applied
+		assert.Equal(t, "3000", c.WebServer.Port)
+
+		// The listeners are notified
+		select {
+		case <-reloaded:
+		default:
+			t.Fatal("reloaded channel was not closed")
+		}
+		assert.NotEqual(t, reloaded, c.Reloaded())
+	})
+
+	t.Run("invalid config is not applied", func(t *testing.T) {
+		c, path := newTestReloadConfig(t)
+		writeTestConfigFile(t, path, map[string]interface{}{
+			"log_level":       "debug",
+			"rpc_connections": []map[string]string{},
+		})
+		result, err := c.Reload(nil)
+		require.ErrorIs(t, err, ErrNoRPCConnections)
+		assert.Nil(t, result)
+		assert.Empty(t, c.Reloadable().LogLevel)
+		assert.Len(t, c.Reloadable().RPCConnections, 1)
+	})
+
+	t.Run("failed validation is not applied", func(t *testing.T) {
+		c, path := newTestReloadConfig(t)
+		writeTestConfigFile(t, path, map[string]interface{}{"alert_webhook_url": "https://new.webhook.url"})
+		result, err := c.Reload(func(*Config) error {
+			return errTestInvalid
+		})
+		require.ErrorIs(t, err, errTestInvalid)
+		assert.Nil(t, result)
+		assert.Equal(t, "https://webhook.url", c.Reloadable().AlertWebhookURL)
+	})
+
+	t.Run("the settings can be read while reloading", func(t *testing.T) {
+		c, path := newTestReloadConfig(t)
+		pool, ok := c.Services.Node.(*NodePool)
+		require.True(t, ok)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				_ = c.Reloadable().AlertProcessingInterval
+				_ = pool.GetRPCHost()
+				_ = pool.Health()
+			}
+		}()
+		for i := 0; i < 10; i++ {
+			writeTestConfigFile(t, path, map[string]interface{}{
+				"alert_processing_interval": fmt.Sprintf("%dm", i+1),
+				"rpc_connections":           []map[string]string{{"user": "new", "password": "new", "host": fmt.Sprintf("http://localhost:%d", 18332+i)}},
+			})
+			_, err := c.Reload(nil)
+			require.NoError(t, err)
+		}
+		<-done
+		assert.Equal(t, 10*time.Minute, c.Reloadable().AlertProcessingInterval)
+		assert.Equal(t, "http://localhost:18341", pool.GetRPCHost())
+	})
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// secretFilePrefix is the prefix of a secret that is read from a file (file:///run/secrets/rpc_password)
+const secretFilePrefix = "file://"
+
+// secretEnvReference matches an environment variable reference in a secret (${RPC_PASSWORD})
+var secretEnvReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+// secretFile is a secret that can be read from a file (the *_file variant of the setting)
+type secretFile struct {
+	file    string  // Path of the file with the secret
+	setting string  // Name of the *_file setting
+	value   *string // The secret setting
+}
+
+// secretFiles returns the *_file variants of the secret settings
+func (c *Config) secretFiles() []secretFile {
+	files := []secretFile{
+		{file: c.Datastore.PasswordFile, setting: "datastore.password_file", value: &c.Datastore.Password},
+		{file: c.P2P.PrivateKeyFile, setting: "p2p.private_key_file", value: &c.P2P.PrivateKey},
+		{file: c.WebServer.AdminTokenFile, setting: "web_server.admin_token_file", value: &c.WebServer.AdminToken},
+	}
+	if c.Datastore.SQLRead != nil {
+		files = append(files, secretFile{file: c.Datastore.SQLRead.PasswordFile, setting: "datastore.sql_read.password_file", value: &c.Datastore.SQLRead.Password})
+	}
+	if c.Datastore.SQLWrite != nil {
+		files = append(files, secretFile{file: c.Datastore.SQLWrite.PasswordFile, setting: "datastore.sql_write.password_file", value: &c.Datastore.SQLWrite.Password})
+	}
+	if c.Datastore.Mongo != nil {
+		files = append(files, secretFile{file: c.Datastore.Mongo.URIFile, setting: "datastore.mongo.uri_file", value: &c.Datastore.Mongo.URI})
+	}
+	for i := range c.RPCConnections {
+		files = append(files,
+			secretFile{file: c.RPCConnections[i].PasswordFile, setting: fmt.Sprintf("rpc_connections[%d].password_file", i), value: &c.RPCConnections[i].Password},
+			secretFile{file: c.RPCConnections[i].UserFile, setting: fmt.Sprintf("rpc_connections[%d].user_file", i), value: &c.RPCConnections[i].User},
+		)
+	}
+	for i := range c.Webhook.Endpoints {
+		files = append(files,
+			secretFile{file: c.Webhook.Endpoints[i].SecretFile, setting: fmt.Sprintf("webhook.endpoints[%d].secret_file", i), value: &c.Webhook.Endpoints[i].Secret},
+			secretFile{file: c.Webhook.Endpoints[i].Email.PasswordFile, setting: fmt.Sprintf("webhook.endpoints[%d].email.password_file", i), value: &c.Webhook.Endpoints[i].Email.Password},
+		)
+	}
+	return files
+}
+
+// secretValues returns the secret settings that can be a reference (by name)
+func (c *Config) secretValues() map[string]*string {
+	values := map[string]*string{
+		"datastore.password":     &c.Datastore.Password,
+		"p2p.private_key":        &c.P2P.PrivateKey,
+		"web_server.admin_token": &c.WebServer.AdminToken,
+	}
+	if c.Datastore.SQLRead != nil {
+		values["datastore.sql_read.password"] = &c.Datastore.SQLRead.Password
+	}
+	if c.Datastore.SQLWrite != nil {
+		values["datastore.sql_write.password"] = &c.Datastore.SQLWrite.Password
+	}
+	if c.Datastore.Mongo != nil {
+		values["datastore.mongo.uri"] = &c.Datastore.Mongo.URI
+	}
+	for i := range c.RPCConnections {
+		values[fmt.Sprintf("rpc_connections[%d].password", i)] = &c.RPCConnections[i].Password
+		values[fmt.Sprintf("rpc_connections[%d].user", i)] = &c.RPCConnections[i].User
+	}
+	for i := range c.Webhook.Endpoints {
+		values[fmt.Sprintf("webhook.endpoints[%d].secret", i)] = &c.Webhook.Endpoints[i].Secret
+		values[fmt.Sprintf("webhook.endpoints[%d].email.password", i)] = &c.Webhook.Endpoints[i].Email.Password
+	}
+	return values
+}
+
+// resolveSecrets will read the secrets from the *_file settings, and replace the file:// and ${ENV} references
+// in the secret settings with the secret (e.g. mounted Kubernetes secrets), and return all the problems
+func (c *Config) resolveSecrets() ValidationErrors {
+	var problems ValidationErrors
+
+	// The *_file variants (the secret itself must be empty)
+	for _, secret := range c.secretFiles() {
+		if len(secret.file) == 0 {
+			continue
+		} else if len(*secret.value) > 0 {
+			problems.add(secret.setting, ErrSecretBothSet)
+			continue
+		}
+		value, err := readSecretFile(secret.file)
+		if err != nil {
+			problems.add(secret.setting, err)
+			continue
+		}
+		*secret.value = value
+	}
+
+	// The headers files (the headers must be empty)
+	for i := range c.Webhook.Endpoints {
+		endpoint := &c.Webhook.Endpoints[i]
+		if len(endpoint.HeadersFile) == 0 {
+			continue
+		}
+		setting := fmt.Sprintf("webhook.endpoints[%d].headers_file", i)
+		if len(endpoint.Headers) > 0 {
+			problems.add(setting, ErrSecretBothSet)
+			continue
+		}
+		headers, err := readHeadersFile(endpoint.HeadersFile)
+		if err != nil {
+			problems.add(setting, err)
+			continue
+		}
+		endpoint.Headers = headers
+	}
+
+	// The references in the secrets
+	for setting, value := range c.secretValues() {
+		if err := resolveSecret(value); err != nil {
+			problems.add(setting, err)
+		}
+	}
+
+	problems.sort()
+	return problems
+}
+
+// resolveSecret will replace a file:// reference with the contents of the file, or the ${ENV} references
+// with the environment variables
+func resolveSecret(value *string) (err error) {
+	if strings.HasPrefix(*value, secretFilePrefix) {
+		*value, err = readSecretFile(strings.TrimPrefix(*value, secretFilePrefix))
+		return err
+	}
+
+	*value = secretEnvReference.ReplaceAllStringFunc(*value, func(reference string) string {
+		name := secretEnvReference.FindStringSubmatch(reference)[1]
+		envValue, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("%w: %s", ErrSecretEnvNotSet, name)
+		}
+		return envValue
+	})
+	return err
+}
+
+// readHeadersFile will read the headers from the file, one "Name: value" per line (the empty lines and the
+// lines starting with # are skipped)
+func readHeadersFile(path string) (map[string]string, error) {
+	contents, err := readSecretFile(path)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string)
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || len(strings.TrimSpace(name)) == 0 {
+			return nil, fmt.Errorf("%w: %q is not \"Name: value\"", ErrSecretHeadersFile, line)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// readSecretFile will read the secret from the file (without the surrounding whitespace or trailing newline)
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path) //nolint:gosec // The path is set by the operator
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrSecretFileNotFound, err.Error())
+	}
+	return strings.TrimSpace(string(b)), nil
+}
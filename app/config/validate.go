@@ -0,0 +1,326 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/kvstore"
+	"github.com/bitcoinschema/go-bitcoin"
+	"github.com/mrz1836/go-datastore"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// domainLabel is a valid label of a domain name (letters, digits and hyphens, not starting or ending with a hyphen)
+var domainLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// Log levels (only debug changes what is logged)
+var logLevels = []string{"", "debug", "info", "warn", "error"}
+
+type (
+
+	// ValidationError is a problem with a configuration setting
+	ValidationError struct {
+		Err     error  // The problem (one of the configuration errors)
+		Setting string // The setting by name, nested with a period (p2p.ip, rpc_connections[0].host)
+	}
+
+	// ValidationErrors are all the problems found in the configuration
+	ValidationErrors []*ValidationError
+)
+
+// Error returns the setting and the problem
+func (e *ValidationError) Error() string {
+	return e.Setting + ": " + e.Err.Error()
+}
+
+// Unwrap returns the problem (for errors.Is)
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns all the problems on one line
+func (e ValidationErrors) Error() string {
+	problems := make([]string, 0, len(e))
+	for _, problem := range e {
+		problems = append(problems, problem.Error())
+	}
+	return "invalid configuration: " + strings.Join(problems, "; ")
+}
+
+// Unwrap returns all the problems (for errors.Is and errors.As)
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, problem := range e {
+		errs = append(errs, problem)
+	}
+	return errs
+}
+
+// add will add a problem with a setting
+func (e *ValidationErrors) add(setting string, err error) {
+	*e = append(*e, &ValidationError{Err: err, Setting: setting})
+}
+
+// sort will sort the problems by setting
+func (e ValidationErrors) sort() {
+	sort.SliceStable(e, func(i, j int) bool {
+		return e[i].Setting < e[j].Setting
+	})
+}
+
+// err returns the problems as an error (nil if there are none)
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate will check every setting and return all the problems found (as ValidationErrors), including
+// settings that are not in the configuration (unknown keys in the config file)
+//
+// The default values are set when the config file is loaded, so empty optional settings are valid
+func (c *Config) Validate() error {
+	var problems ValidationErrors
+
+	// Settings that do not exist (most likely a typo)
+	for _, setting := range c.unknownSettings {
+		problems.add(setting, ErrUnknownSetting)
+	}
+
+	// Secrets that could not be read from a file or an environment variable
+	problems = append(problems, c.secretProblems...)
+
+	// Genesis keys
+	if len(c.GenesisKeys) == 0 {
+		problems.add("genesis_keys", ErrNoGenesisKeys)
+	}
+	for i, key := range c.GenesisKeys {
+		if _, err := bitcoin.PubKeyFromString(key); err != nil {
+			problems.add(fmt.Sprintf("genesis_keys[%d]", i), ErrInvalidGenesisKey)
+		}
+	}
+
+	// RPC connections
+	if len(c.RPCConnections) == 0 {
+		problems.add("rpc_connections", ErrNoRPCConnections)
+	}
+	for i, connection := range c.RPCConnections {
+		if len(connection.Host) == 0 {
+			problems.add(fmt.Sprintf("rpc_connections[%d].host", i), ErrNoRPCHost)
+		} else if !isURL(connection.Host, "http", "https") {
+			problems.add(fmt.Sprintf("rpc_connections[%d].host", i), ErrInvalidURL)
+		}
+		if len(connection.CookieFile) > 0 && (len(connection.User) > 0 || len(connection.Password) > 0) {
+			problems.add(fmt.Sprintf("rpc_connections[%d].cookie_file", i), ErrInvalidCookieFile)
+		}
+		problems.duration(fmt.Sprintf("rpc_connections[%d].timeout", i), connection.Timeout)
+	}
+	problems.duration("node_health.circuit_open_timeout", c.NodeHealth.CircuitOpenTimeout)
+	problems.duration("node_health.probe_interval", c.NodeHealth.ProbeInterval)
+	if c.NodeHealth.FailureThreshold < 0 {
+		problems.add("node_health.failure_threshold", ErrInvalidCount)
+	}
+
+	// General settings
+	if !contains(logLevels, strings.ToLower(c.LogLevel)) {
+		problems.add("log_level", ErrInvalidLogLevel)
+	}
+	problems.duration("alert_processing_interval", c.AlertProcessingInterval)
+	problems.duration("alert_verification_interval", c.AlertVerificationInterval)
+	problems.duration("chain_height_interval", c.ChainHeightInterval)
+	problems.duration("config_watch_interval", c.ConfigWatchInterval)
+
+	c.validateP2P(&problems)
+	c.validateServers(&problems)
+	c.validateDatastore(&problems)
+	c.validateWebhooks(&problems)
+
+	return problems.err()
+}
+
+// validateP2P will check the P2P settings
+func (c *Config) validateP2P(problems *ValidationErrors) {
+	if len(strings.TrimSpace(c.P2P.IP)) == 0 {
+		problems.add("p2p.ip", ErrNoP2PIP)
+	} else if ip := net.ParseIP(c.P2P.IP); ip == nil || ip.To4() == nil {
+		problems.add("p2p.ip", ErrInvalidIP) // The listen address is /ip4/<ip>/tcp/<port>
+	}
+	if len(strings.TrimSpace(c.P2P.Port)) == 0 {
+		problems.add("p2p.port", ErrNoP2PPort)
+	} else {
+		problems.port("p2p.port", c.P2P.Port)
+	}
+	if len(c.P2P.BroadcastIP) > 0 && !isHost(c.P2P.BroadcastIP) {
+		problems.add("p2p.broadcast_ip", ErrInvalidHost)
+	}
+	if len(c.P2P.BootstrapPeer) > 0 {
+		if _, err := multiaddr.NewMultiaddr(c.P2P.BootstrapPeer); err != nil {
+			problems.add("p2p.bootstrap_peer", fmt.Errorf("%w: %s", ErrInvalidMultiaddr, err.Error()))
+		}
+	}
+	switch c.P2P.DHTMode {
+	case "", "client", "server":
+	default:
+		problems.add("p2p.dht_mode", ErrInvalidDHTMode)
+	}
+	if len(c.P2P.PrivateKey) > 0 {
+		if _, err := hex.DecodeString(c.P2P.PrivateKey); err != nil {
+			problems.add("p2p.private_key", ErrInvalidPrivateKey)
+		}
+	}
+	problems.duration("p2p.peer_discovery_interval", c.P2P.PeerDiscoveryInterval)
+}
+
+// validateServers will check the web server and gRPC server settings
+func (c *Config) validateServers(problems *ValidationErrors) {
+	if len(c.WebServer.Port) > 0 {
+		problems.port("web_server.port", c.WebServer.Port)
+	}
+	problems.duration("web_server.idle_timeout", c.WebServer.IdleTimeout)
+	problems.duration("web_server.read_timeout", c.WebServer.ReadTimeout)
+	problems.duration("web_server.write_timeout", c.WebServer.WriteTimeout)
+	problems.duration("web_server.cors.max_age", c.WebServer.CORS.MaxAge)
+	for i, origin := range c.WebServer.CORS.AllowedOrigins {
+		if origin != "*" && !isURL(origin, "http", "https") {
+			problems.add(fmt.Sprintf("web_server.cors.allowed_origins[%d]", i), ErrInvalidCORSOrigin)
+		}
+	}
+	if c.GRPC.Enabled {
+		problems.port("grpc.port", c.GRPC.Port)
+	}
+}
+
+// validateDatastore will check the datastore settings (for the engine that is used)
+func (c *Config) validateDatastore(problems *ValidationErrors) {
+	switch c.Datastore.Engine {
+	case datastore.SQLite, kvstore.Engine:
+	case datastore.MySQL, datastore.PostgreSQL:
+		for i, sqlConfig := range []*SQLConfig{c.Datastore.SQLRead, c.Datastore.SQLWrite} {
+			if sqlConfig == nil {
+				continue
+			}
+			name := "datastore.sql_read"
+			if i == 1 {
+				name = "datastore.sql_write"
+			}
+			if len(sqlConfig.Port) > 0 {
+				problems.port(name+".port", sqlConfig.Port)
+			}
+			problems.duration(name+".max_connection_idle_time", sqlConfig.MaxConnectionIdleTime)
+			problems.duration(name+".max_connection_time", sqlConfig.MaxConnectionTime)
+			problems.duration(name+".tx_timeout", sqlConfig.TxTimeout)
+		}
+	case datastore.MongoDB:
+		if c.Datastore.Mongo == nil || len(c.Datastore.Mongo.URI) == 0 || len(c.Datastore.Mongo.DatabaseName) == 0 {
+			problems.add("datastore.mongo", ErrNoMongoConfig)
+		} else if !isURL(c.Datastore.Mongo.URI, "mongodb", "mongodb+srv") {
+			problems.add("datastore.mongo.uri", ErrInvalidMongoURI)
+		}
+	default:
+		problems.add("datastore.engine", ErrDatastoreUnsupported)
+	}
+}
+
+// validateWebhooks will check the webhook delivery settings and endpoints
+func (c *Config) validateWebhooks(problems *ValidationErrors) {
+	if len(c.AlertWebhookURL) > 0 && !isURL(c.AlertWebhookURL, "http", "https") {
+		problems.add("alert_webhook_url", ErrInvalidURL)
+	}
+	problems.duration("webhook.delivery_interval", c.Webhook.DeliveryInterval)
+	problems.duration("webhook.initial_backoff", c.Webhook.InitialBackoff)
+	problems.duration("webhook.max_backoff", c.Webhook.MaxBackoff)
+
+	// The alert_webhook_url is an endpoint with the default name
+	names := make(map[string]bool, len(c.Webhook.Endpoints)+1)
+	if len(c.AlertWebhookURL) > 0 {
+		names[DefaultWebhookEndpointName] = true
+	}
+
+	for i, endpoint := range c.Webhook.Endpoints {
+		setting := fmt.Sprintf("webhook.endpoints[%d]", i)
+		if len(endpoint.Name) == 0 {
+			problems.add(setting+".name", ErrWebhookNoName)
+		} else if names[endpoint.Name] {
+			problems.add(setting+".name", fmt.Errorf("%w: %s", ErrWebhookDuplicateName, endpoint.Name))
+		}
+		names[endpoint.Name] = true
+		problems.duration(setting+".timeout", endpoint.Timeout)
+
+		switch endpoint.Format {
+		case "", WebhookFormatJSON, WebhookFormatSlack, WebhookFormatTeams:
+			if !isURL(endpoint.URL, "http", "https") {
+				problems.add(setting+".url", ErrWebhookInvalidURL)
+			}
+		case WebhookFormatEmail:
+			if !isURL(endpoint.URL, "smtp") {
+				problems.add(setting+".url", ErrWebhookInvalidURL)
+			}
+			if len(endpoint.Email.From) == 0 || len(endpoint.Email.To) == 0 {
+				problems.add(setting+".email", ErrWebhookEmailAddress)
+			}
+		default:
+			problems.add(setting+".format", ErrWebhookInvalidFormat)
+		}
+	}
+}
+
+// duration will add a problem if the duration is negative
+func (e *ValidationErrors) duration(setting string, value time.Duration) {
+	if value < 0 {
+		e.add(setting, ErrInvalidDuration)
+	}
+}
+
+// port will add a problem if the port is not a number from 1 to 65535
+func (e *ValidationErrors) port(setting, value string) {
+	if port, err := strconv.ParseUint(value, 10, 16); err != nil || port == 0 {
+		e.add(setting, ErrInvalidPort)
+	}
+}
+
+// isURL returns true if the value is a URL with a host and one of the schemes
+func isURL(value string, schemes ...string) bool {
+	u, err := url.Parse(value)
+	if err != nil || len(u.Host) == 0 {
+		return false
+	}
+	return contains(schemes, strings.ToLower(u.Scheme))
+}
+
+// isHost returns true if the value is an IPv4 address or a domain name
+func isHost(value string) bool {
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.To4() != nil
+	}
+	if len(value) > 253 {
+		return false
+	}
+	labels := strings.Split(strings.TrimSuffix(value, "."), ".")
+	for _, label := range labels {
+		if !domainLabel.MatchString(label) {
+			return false
+		}
+	}
+
+	// A numeric top-level label is an invalid IP address (999.1.1.1), not a domain name
+	_, err := strconv.Atoi(labels[len(labels)-1])
+	return err != nil
+}
+
+// contains returns true if the value is in the list
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
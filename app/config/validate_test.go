@@ -0,0 +1,262 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestValidConfig will load the (valid) test environment config
+func newTestValidConfig(t *testing.T) *Config {
+	t.Setenv(EnvironmentKey, EnvironmentTest)
+	t.Setenv(EnvironmentCustomFilePath, "")
+
+	c, err := loadValidConfigFile(nil)
+	require.NoError(t, err)
+	return c
+}
+
+// problemSettings returns the settings with problems (in order)
+func problemSettings(t *testing.T, err error) []string {
+	var problems ValidationErrors
+	require.ErrorAs(t, err, &problems)
+	settings := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		settings = append(settings, problem.Setting)
+	}
+	return settings
+}
+
+// webhookProblems returns the problems with the webhook settings
+func webhookProblems(c *Config) error {
+	var problems ValidationErrors
+	c.validateWebhooks(&problems)
+	return problems.err()
+}
+
+// TestConfig_Validate tests the method Validate()
+func TestConfig_Validate(t *testing.T) {
+
+	t.Run("valid config", func(t *testing.T) {
+		c := newTestValidConfig(t)
+		require.NoError(t, c.Validate())
+
+		c.P2P.BroadcastIP = "alerts.example.com"
+		c.P2P.BootstrapPeer = "/ip4/1.2.3.4/tcp/9906/p2p/12D3KooWJz1FnBVhWwUTzXKVcDShKUkfEKvdfYT9XCD5AmJkg8Lk"
+		c.P2P.DHTMode = "client"
+		c.WebServer.CORS.AllowedOrigins = []string{"*", "https://example.com"}
+		c.Datastore.Engine = "kvstore"
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("all problems are returned", func(t *testing.T) {
+		c := newTestValidConfig(t)
+		c.GenesisKeys = append(c.GenesisKeys, "not-a-key")
+		c.RPCConnections[0].Host = "localhost:8333"
+		c.RPCConnections[0].CookieFile = "/root/.bitcoin/.cookie"
+		c.LogLevel = "verbose"
+		c.P2P.IP = "localhost"
+		c.P2P.Port = "70000"
+		c.P2P.BroadcastIP = "999.1.1.1"
+		c.P2P.BootstrapPeer = "1.2.3.4:9906"
+		c.P2P.DHTMode = "full"
+		c.P2P.PrivateKey = "xyz"
+		c.WebServer.ReadTimeout = -time.Second
+		c.WebServer.CORS.AllowedOrigins = []string{"example.com"}
+		c.Datastore.Engine = "oracle"
+		c.AlertWebhookURL = "ftp://webhook.url"
+
+		err := c.Validate()
+		require.Error(t, err)
+		assert.Equal(t, []string{
+			"genesis_keys[5]",
+			"rpc_connections[0].host",
+			"rpc_connections[0].cookie_file",
+			"log_level",
+			"p2p.ip",
+			"p2p.port",
+			"p2p.broadcast_ip",
+			"p2p.bootstrap_peer",
+			"p2p.dht_mode",
+			"p2p.private_key",
+			"web_server.read_timeout",
+			"web_server.cors.allowed_origins[0]",
+			"datastore.engine",
+			"alert_webhook_url",
+		}, problemSettings(t, err))
+
+		// The problems can be checked by error
+		require.ErrorIs(t, err, ErrInvalidGenesisKey)
+		require.ErrorIs(t, err, ErrInvalidURL)
+		require.ErrorIs(t, err, ErrInvalidCookieFile)
+		require.ErrorIs(t, err, ErrInvalidLogLevel)
+		require.ErrorIs(t, err, ErrInvalidIP)
+		require.ErrorIs(t, err, ErrInvalidPort)
+		require.ErrorIs(t, err, ErrInvalidHost)
+		require.ErrorIs(t, err, ErrInvalidMultiaddr)
+		require.ErrorIs(t, err, ErrInvalidDHTMode)
+		require.ErrorIs(t, err, ErrInvalidPrivateKey)
+		require.ErrorIs(t, err, ErrInvalidDuration)
+		require.ErrorIs(t, err, ErrInvalidCORSOrigin)
+		require.ErrorIs(t, err, ErrDatastoreUnsupported)
+		assert.Contains(t, err.Error(), "p2p.port: "+ErrInvalidPort.Error())
+	})
+
+	t.Run("missing settings", func(t *testing.T) {
+		c := newTestValidConfig(t)
+		c.GenesisKeys = nil
+		c.RPCConnections = nil
+		c.P2P.IP = " "
+		c.P2P.Port = ""
+
+		err := c.Validate()
+		assert.Equal(t, []string{"genesis_keys", "rpc_connections", "p2p.ip", "p2p.port"}, problemSettings(t, err))
+		require.ErrorIs(t, err, ErrNoGenesisKeys)
+		require.ErrorIs(t, err, ErrNoRPCConnections)
+		require.ErrorIs(t, err, ErrNoP2PIP)
+		require.ErrorIs(t, err, ErrNoP2PPort)
+	})
+
+	t.Run("ports", func(t *testing.T) {
+		c := newTestValidConfig(t)
+		for _, port := range []string{"1", "9906", "65535"} {
+			c.P2P.Port = port
+			require.NoError(t, c.Validate(), port)
+		}
+		for _, port := range []string{"0", "65536", "-1", "port"} {
+			c.P2P.Port = port
+			require.ErrorIs(t, c.Validate(), ErrInvalidPort, port)
+		}
+	})
+
+	t.Run("mongodb", func(t *testing.T) {
+		c := newTestValidConfig(t)
+		c.Datastore.Engine = "mongodb"
+		require.ErrorIs(t, c.Validate(), ErrNoMongoConfig)
+	})
+}
+
+// TestLoadValidConfigFile_Strict tests the unknown settings and negative durations in a config file
+func TestLoadValidConfigFile_Strict(t *testing.T) {
+
+	t.Run("unknown settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeTestConfigFile(t, path, map[string]interface{}{
+			"alert_webhok_url": "https://webhook.url",
+			"p2p": map[string]interface{}{
+				"ip":      "192.168.1.1",
+				"port":    "8000",
+				"dht_mod": "client",
+			},
+			"rpc_connections": []map[string]string{{"user": "galt", "password": "galt", "host": "http://localhost:8333", "hots": "x"}},
+		})
+		t.Setenv(EnvironmentKey, EnvironmentTest)
+		t.Setenv(EnvironmentCustomFilePath, path)
+
+		c, err := loadValidConfigFile(nil)
+		assert.Nil(t, c)
+		require.ErrorIs(t, err, ErrUnknownSetting)
+		assert.Equal(t, []string{"alert_webhok_url", "p2p.dht_mod", "rpc_connections[0].hots"}, problemSettings(t, err))
+	})
+
+	t.Run("negative durations", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeTestConfigFile(t, path, map[string]interface{}{
+			"alert_processing_interval": "-1m",
+			"webhook":                   map[string]interface{}{"max_backoff": "-1h"},
+		})
+		t.Setenv(EnvironmentKey, EnvironmentTest)
+		t.Setenv(EnvironmentCustomFilePath, path)
+
+		c, err := loadValidConfigFile(nil)
+		assert.Nil(t, c)
+		require.ErrorIs(t, err, ErrInvalidDuration)
+		assert.Equal(t, []string{"alert_processing_interval", "webhook.max_backoff"}, problemSettings(t, err))
+	})
+
+	t.Run("environment files only have settings", func(t *testing.T) {
+		t.Setenv(EnvironmentCustomFilePath, "")
+		for _, environment := range environments {
+			t.Setenv(EnvironmentKey, environment.(string))
+			c, err := readConfigFile()
+			require.NoError(t, err)
+			assert.Empty(t, c.unknownSettings, environment)
+		}
+	})
+}
+
+// TestConfig_validateWebhooks tests the method validateWebhooks()
+func TestConfig_validateWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid endpoints", func(t *testing.T) {
+		c := &Config{AlertWebhookURL: "https://webhook.url", Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "slack", URL: "https://hooks.slack.com/services/test"},
+			{Name: "pagerduty", URL: "http://localhost:8080/alerts"},
+		}}}
+		require.NoError(t, webhookProblems(c))
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{URL: "https://hooks.slack.com/services/test"},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookNoName)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "slack", URL: "https://hooks.slack.com/services/test"},
+			{Name: "slack", URL: "https://hooks.slack.com/services/other"},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookDuplicateName)
+	})
+
+	t.Run("name collides with alert_webhook_url", func(t *testing.T) {
+		c := &Config{AlertWebhookURL: "https://webhook.url", Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: DefaultWebhookEndpointName, URL: "https://hooks.slack.com/services/test"},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookDuplicateName)
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "slack", URL: "hooks.slack.com/services/test"},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookInvalidURL)
+	})
+
+	t.Run("formats", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "slack", Format: WebhookFormatSlack, URL: "https://hooks.slack.com/services/test"},
+			{Name: "teams", Format: WebhookFormatTeams, URL: "https://example.webhook.office.com/test"},
+			{Name: "email", Format: WebhookFormatEmail, URL: "smtp://mail.example.com:587", Email: WebhookEmailConfig{
+				From: "alerts@example.com", To: []string{"soc@example.com"},
+			}},
+		}}}
+		require.NoError(t, webhookProblems(c))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "discord", Format: "discord", URL: "https://discord.com/api/webhooks/test"},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookInvalidFormat)
+	})
+
+	t.Run("email endpoint", func(t *testing.T) {
+		c := &Config{Webhook: WebhookConfig{Endpoints: []WebhookEndpointConfig{
+			{Name: "email", Format: WebhookFormatEmail, URL: "https://mail.example.com", Email: WebhookEmailConfig{
+				From: "alerts@example.com", To: []string{"soc@example.com"},
+			}},
+		}}}
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookInvalidURL)
+
+		c.Webhook.Endpoints[0].URL = "smtp://mail.example.com:587"
+		c.Webhook.Endpoints[0].Email.To = nil
+		require.ErrorIs(t, webhookProblems(c), ErrWebhookEmailAddress)
+	})
+}
@@ -0,0 +1,137 @@
+// Package events broadcasts accepted alerts to in-process subscribers (e.g. the alert stream)
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// DefaultSubscriberBuffer is the number of events a subscriber can fall behind before it is dropped
+const DefaultSubscriberBuffer = 64
+
+// AlertEvent is an accepted alert as sent to subscribers
+type AlertEvent struct {
+	Alert         json.RawMessage  `json:"alert"` // Decoded alert message
+	AlertType     models.AlertType `json:"alert_type"`
+	AlertTypeName string           `json:"alert_type_name"`
+	Hash          string           `json:"hash"`
+	Message       string           `json:"message"`   // Alert message summary (e.g. Informational: <text>)
+	Processed     bool             `json:"processed"` // Outcome of the alert action
+	Raw           string           `json:"raw"`       // Raw signed alert (hex)
+	Sequence      uint32           `json:"sequence"`
+}
+
+// NewAlertEvent will create the event for an alert
+//
+// Alerts loaded from the datastore need ReadRaw() first.
+func NewAlertEvent(ctx context.Context, alert *models.AlertMessage) (*AlertEvent, error) {
+	am := alert.ProcessAlertMessage()
+	if am == nil {
+		return nil, fmt.Errorf("alert type [%d] is not supported", alert.GetAlertType())
+	}
+
+	// Decode the alert (compact, an SSE data line cannot contain new lines)
+	var decoded bytes.Buffer
+	if err := json.Compact(&decoded, am.ToJSON(ctx)); err != nil {
+		return nil, err
+	}
+	if err := am.Read(alert.GetRawMessage()); err != nil {
+		return nil, err
+	}
+
+	return &AlertEvent{
+		Alert:         decoded.Bytes(),
+		AlertType:     alert.GetAlertType(),
+		AlertTypeName: alert.GetAlertType().Name(),
+		Hash:          alert.Hash,
+		Message:       am.MessageString(),
+		Processed:     alert.Processed,
+		Raw:           alert.Raw,
+		Sequence:      alert.SequenceNumber,
+	}, nil
+}
+
+// GetAlertEventsAfterSequence will load the events for all saved alerts after the given sequence number
+//
+// This is used to replay what a resuming subscriber missed.
+func GetAlertEventsAfterSequence(ctx context.Context, conf *config.Config, sequenceNumber uint32) ([]*AlertEvent, error) {
+	alerts, err := models.GetAlertsAfterSequence(ctx, sequenceNumber, nil, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	}
+	alertEvents := make([]*AlertEvent, 0, len(alerts))
+	for _, alert := range alerts {
+		alert.SetOptions(model.WithAllDependencies(conf))
+		if err = alert.ReadRaw(); err != nil {
+			return nil, fmt.Errorf("failed to read alert %d: %w", alert.SequenceNumber, err)
+		}
+		var event *AlertEvent
+		if event, err = NewAlertEvent(ctx, alert); err != nil {
+			return nil, fmt.Errorf("failed to create event for alert %d: %w", alert.SequenceNumber, err)
+		}
+		alertEvents = append(alertEvents, event)
+	}
+	return alertEvents, nil
+}
+
+// Broadcaster fans accepted alerts out to subscribers
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan *AlertEvent]struct{}
+}
+
+// NewBroadcaster will create a new broadcaster
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: make(map[chan *AlertEvent]struct{})}
+}
+
+// Subscribe will return a channel of events and a function to unsubscribe
+//
+// Publishing never blocks: if a subscriber falls more than buffer events behind, its
+// channel is closed and it should reconnect and resume from the last sequence it saw.
+func (b *Broadcaster) Subscribe(buffer int) (<-chan *AlertEvent, func()) {
+	ch := make(chan *AlertEvent, buffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(ch)
+	}
+}
+
+// Publish will send the event to all subscribers
+func (b *Broadcaster) Publish(event *AlertEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.remove(ch)
+		}
+	}
+}
+
+// Subscribers returns the number of subscribers
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove will close and remove a subscriber (the lock must be held)
+func (b *Broadcaster) remove(ch chan *AlertEvent) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
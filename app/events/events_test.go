@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewAlertEvent will test the method NewAlertEvent()
+func TestNewAlertEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("informational alert", func(t *testing.T) {
+		alert := &models.AlertMessage{SequenceNumber: 3, Processed: true}
+		alert.SetAlertType(models.AlertTypeInformational)
+		alert.SetRawMessage(append([]byte{5}, "hello"...))
+		_ = alert.Serialize()
+
+		event, err := NewAlertEvent(context.Background(), alert)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), event.Sequence)
+		assert.Equal(t, models.AlertTypeInformational, event.AlertType)
+		assert.Equal(t, "Informational", event.AlertTypeName)
+		assert.Equal(t, alert.Hash, event.Hash)
+		assert.Equal(t, alert.Raw, event.Raw)
+		assert.True(t, event.Processed)
+		assert.NotContains(t, string(event.Alert), "\n")
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(event.Alert, &decoded))
+		assert.InDelta(t, 5, decoded["message_length"], 0)
+	})
+
+	t.Run("unsupported alert type", func(t *testing.T) {
+		_, err := NewAlertEvent(context.Background(), &models.AlertMessage{})
+		require.Error(t, err)
+	})
+}
+
+// TestBroadcaster will test publishing to subscribers
+func TestBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all subscribers get the event", func(t *testing.T) {
+		b := NewBroadcaster()
+		ch1, unsubscribe1 := b.Subscribe(1)
+		ch2, unsubscribe2 := b.Subscribe(1)
+		defer unsubscribe1()
+		defer unsubscribe2()
+		assert.Equal(t, 2, b.Subscribers())
+
+		b.Publish(&AlertEvent{Sequence: 1})
+		assert.Equal(t, uint32(1), (<-ch1).Sequence)
+		assert.Equal(t, uint32(1), (<-ch2).Sequence)
+	})
+
+	t.Run("unsubscribe closes the channel", func(t *testing.T) {
+		b := NewBroadcaster()
+		ch, unsubscribe := b.Subscribe(1)
+		unsubscribe()
+		unsubscribe() // idempotent
+		_, ok := <-ch
+		assert.False(t, ok)
+		assert.Equal(t, 0, b.Subscribers())
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		b := NewBroadcaster()
+		ch, unsubscribe := b.Subscribe(1)
+		defer unsubscribe()
+
+		b.Publish(&AlertEvent{Sequence: 1})
+		b.Publish(&AlertEvent{Sequence: 2}) // buffer is full
+		assert.Equal(t, 0, b.Subscribers())
+
+		event, ok := <-ch
+		require.True(t, ok)
+		assert.Equal(t, uint32(1), event.Sequence)
+		_, ok = <-ch
+		assert.False(t, ok)
+	})
+}
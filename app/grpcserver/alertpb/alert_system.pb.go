@@ -0,0 +1,678 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: alert_system.proto
+
+package alertpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Alert is an alert message
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint32                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	AlertType     uint32                 `protobuf:"varint,2,opt,name=alert_type,json=alertType,proto3" json:"alert_type,omitempty"`
+	AlertTypeName string                 `protobuf:"bytes,3,opt,name=alert_type_name,json=alertTypeName,proto3" json:"alert_type_name,omitempty"`
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Processed     bool                   `protobuf:"varint,5,opt,name=processed,proto3" json:"processed,omitempty"`
+	// The raw signed alert (hex)
+	Raw string `protobuf:"bytes,6,opt,name=raw,proto3" json:"raw,omitempty"`
+	// The decoded alert message (JSON)
+	Decoded string `protobuf:"bytes,7,opt,name=decoded,proto3" json:"decoded,omitempty"`
+	// A human readable summary of the alert message
+	Message       string `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_alert_system_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Alert) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Alert) GetAlertType() uint32 {
+	if x != nil {
+		return x.AlertType
+	}
+	return 0
+}
+
+func (x *Alert) GetAlertTypeName() string {
+	if x != nil {
+		return x.AlertTypeName
+	}
+	return ""
+}
+
+func (x *Alert) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Alert) GetProcessed() bool {
+	if x != nil {
+		return x.Processed
+	}
+	return false
+}
+
+func (x *Alert) GetRaw() string {
+	if x != nil {
+		return x.Raw
+	}
+	return ""
+}
+
+func (x *Alert) GetDecoded() string {
+	if x != nil {
+		return x.Decoded
+	}
+	return ""
+}
+
+func (x *Alert) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// PublicKey is an alert-system public key
+type PublicKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The compressed public key (hex)
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// The hash of the alert that set the key
+	LastUpdateHash string `protobuf:"bytes,2,opt,name=last_update_hash,json=lastUpdateHash,proto3" json:"last_update_hash,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PublicKey) Reset() {
+	*x = PublicKey{}
+	mi := &file_alert_system_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicKey) ProtoMessage() {}
+
+func (x *PublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicKey.ProtoReflect.Descriptor instead.
+func (*PublicKey) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublicKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PublicKey) GetLastUpdateHash() string {
+	if x != nil {
+		return x.LastUpdateHash
+	}
+	return ""
+}
+
+type GetAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint32                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertRequest) Reset() {
+	*x = GetAlertRequest{}
+	mi := &file_alert_system_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRequest) ProtoMessage() {}
+
+func (x *GetAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRequest) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetAlertRequest) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type ListAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only return alerts after this sequence number
+	AfterSequence *uint32 `protobuf:"varint,1,opt,name=after_sequence,json=afterSequence,proto3,oneof" json:"after_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_alert_system_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListAlertsRequest) GetAfterSequence() uint32 {
+	if x != nil && x.AfterSequence != nil {
+		return *x.AfterSequence
+	}
+	return 0
+}
+
+type ListAlertsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Alerts         []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	LatestSequence uint32                 `protobuf:"varint,2,opt,name=latest_sequence,json=latestSequence,proto3" json:"latest_sequence,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_alert_system_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *ListAlertsResponse) GetLatestSequence() uint32 {
+	if x != nil {
+		return x.LatestSequence
+	}
+	return 0
+}
+
+type GetActiveKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveKeysRequest) Reset() {
+	*x = GetActiveKeysRequest{}
+	mi := &file_alert_system_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveKeysRequest) ProtoMessage() {}
+
+func (x *GetActiveKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveKeysRequest) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{5}
+}
+
+type GetActiveKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*PublicKey           `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveKeysResponse) Reset() {
+	*x = GetActiveKeysResponse{}
+	mi := &file_alert_system_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveKeysResponse) ProtoMessage() {}
+
+func (x *GetActiveKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetActiveKeysResponse) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetActiveKeysResponse) GetKeys() []*PublicKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthRequest) Reset() {
+	*x = GetHealthRequest{}
+	mi := &file_alert_system_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthRequest) ProtoMessage() {}
+
+func (x *GetHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetHealthRequest) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{7}
+}
+
+type GetHealthResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Alert             *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	Sequence          uint32                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Synced            bool                   `protobuf:"varint,3,opt,name=synced,proto3" json:"synced,omitempty"`
+	ActivePeers       int32                  `protobuf:"varint,4,opt,name=active_peers,json=activePeers,proto3" json:"active_peers,omitempty"`
+	UnprocessedAlerts int32                  `protobuf:"varint,5,opt,name=unprocessed_alerts,json=unprocessedAlerts,proto3" json:"unprocessed_alerts,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetHealthResponse) Reset() {
+	*x = GetHealthResponse{}
+	mi := &file_alert_system_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthResponse) ProtoMessage() {}
+
+func (x *GetHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthResponse.ProtoReflect.Descriptor instead.
+func (*GetHealthResponse) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetHealthResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *GetHealthResponse) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *GetHealthResponse) GetSynced() bool {
+	if x != nil {
+		return x.Synced
+	}
+	return false
+}
+
+func (x *GetHealthResponse) GetActivePeers() int32 {
+	if x != nil {
+		return x.ActivePeers
+	}
+	return 0
+}
+
+func (x *GetHealthResponse) GetUnprocessedAlerts() int32 {
+	if x != nil {
+		return x.UnprocessedAlerts
+	}
+	return 0
+}
+
+type WatchAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Send every alert after this sequence number first (unset: only new alerts)
+	AfterSequence *uint32 `protobuf:"varint,1,opt,name=after_sequence,json=afterSequence,proto3,oneof" json:"after_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAlertsRequest) Reset() {
+	*x = WatchAlertsRequest{}
+	mi := &file_alert_system_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAlertsRequest) ProtoMessage() {}
+
+func (x *WatchAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_alert_system_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAlertsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_alert_system_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchAlertsRequest) GetAfterSequence() uint32 {
+	if x != nil && x.AfterSequence != nil {
+		return *x.AfterSequence
+	}
+	return 0
+}
+
+var File_alert_system_proto protoreflect.FileDescriptor
+
+const file_alert_system_proto_rawDesc = "" +
+	"\n" +
+	"\x12alert_system.proto\x12\x0ealertsystem.v1\"\xe2\x01\n" +
+	"\x05Alert\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\rR\bsequence\x12\x1d\n" +
+	"\n" +
+	"alert_type\x18\x02 \x01(\rR\talertType\x12&\n" +
+	"\x0falert_type_name\x18\x03 \x01(\tR\ralertTypeName\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\x12\x1c\n" +
+	"\tprocessed\x18\x05 \x01(\bR\tprocessed\x12\x10\n" +
+	"\x03raw\x18\x06 \x01(\tR\x03raw\x12\x18\n" +
+	"\adecoded\x18\a \x01(\tR\adecoded\x12\x18\n" +
+	"\amessage\x18\b \x01(\tR\amessage\"G\n" +
+	"\tPublicKey\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12(\n" +
+	"\x10last_update_hash\x18\x02 \x01(\tR\x0elastUpdateHash\"-\n" +
+	"\x0fGetAlertRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\rR\bsequence\"R\n" +
+	"\x11ListAlertsRequest\x12*\n" +
+	"\x0eafter_sequence\x18\x01 \x01(\rH\x00R\rafterSequence\x88\x01\x01B\x11\n" +
+	"\x0f_after_sequence\"l\n" +
+	"\x12ListAlertsResponse\x12-\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x15.alertsystem.v1.AlertR\x06alerts\x12'\n" +
+	"\x0flatest_sequence\x18\x02 \x01(\rR\x0elatestSequence\"\x16\n" +
+	"\x14GetActiveKeysRequest\"F\n" +
+	"\x15GetActiveKeysResponse\x12-\n" +
+	"\x04keys\x18\x01 \x03(\v2\x19.alertsystem.v1.PublicKeyR\x04keys\"\x12\n" +
+	"\x10GetHealthRequest\"\xc6\x01\n" +
+	"\x11GetHealthResponse\x12+\n" +
+	"\x05alert\x18\x01 \x01(\v2\x15.alertsystem.v1.AlertR\x05alert\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\rR\bsequence\x12\x16\n" +
+	"\x06synced\x18\x03 \x01(\bR\x06synced\x12!\n" +
+	"\factive_peers\x18\x04 \x01(\x05R\vactivePeers\x12-\n" +
+	"\x12unprocessed_alerts\x18\x05 \x01(\x05R\x11unprocessedAlerts\"S\n" +
+	"\x12WatchAlertsRequest\x12*\n" +
+	"\x0eafter_sequence\x18\x01 \x01(\rH\x00R\rafterSequence\x88\x01\x01B\x11\n" +
+	"\x0f_after_sequence2\xa2\x03\n" +
+	"\vAlertSystem\x12B\n" +
+	"\bGetAlert\x12\x1f.alertsystem.v1.GetAlertRequest\x1a\x15.alertsystem.v1.Alert\x12S\n" +
+	"\n" +
+	"ListAlerts\x12!.alertsystem.v1.ListAlertsRequest\x1a\".alertsystem.v1.ListAlertsResponse\x12\\\n" +
+	"\rGetActiveKeys\x12$.alertsystem.v1.GetActiveKeysRequest\x1a%.alertsystem.v1.GetActiveKeysResponse\x12P\n" +
+	"\tGetHealth\x12 .alertsystem.v1.GetHealthRequest\x1a!.alertsystem.v1.GetHealthResponse\x12J\n" +
+	"\vWatchAlerts\x12\".alertsystem.v1.WatchAlertsRequest\x1a\x15.alertsystem.v1.Alert0\x01B;Z9github.com/bitcoin-sv/alert-system/app/grpcserver/alertpbb\x06proto3"
+
+var (
+	file_alert_system_proto_rawDescOnce sync.Once
+	file_alert_system_proto_rawDescData []byte
+)
+
+func file_alert_system_proto_rawDescGZIP() []byte {
+	file_alert_system_proto_rawDescOnce.Do(func() {
+		file_alert_system_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_alert_system_proto_rawDesc), len(file_alert_system_proto_rawDesc)))
+	})
+	return file_alert_system_proto_rawDescData
+}
+
+var file_alert_system_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_alert_system_proto_goTypes = []any{
+	(*Alert)(nil),                 // 0: alertsystem.v1.Alert
+	(*PublicKey)(nil),             // 1: alertsystem.v1.PublicKey
+	(*GetAlertRequest)(nil),       // 2: alertsystem.v1.GetAlertRequest
+	(*ListAlertsRequest)(nil),     // 3: alertsystem.v1.ListAlertsRequest
+	(*ListAlertsResponse)(nil),    // 4: alertsystem.v1.ListAlertsResponse
+	(*GetActiveKeysRequest)(nil),  // 5: alertsystem.v1.GetActiveKeysRequest
+	(*GetActiveKeysResponse)(nil), // 6: alertsystem.v1.GetActiveKeysResponse
+	(*GetHealthRequest)(nil),      // 7: alertsystem.v1.GetHealthRequest
+	(*GetHealthResponse)(nil),     // 8: alertsystem.v1.GetHealthResponse
+	(*WatchAlertsRequest)(nil),    // 9: alertsystem.v1.WatchAlertsRequest
+}
+var file_alert_system_proto_depIdxs = []int32{
+	0, // 0: alertsystem.v1.ListAlertsResponse.alerts:type_name -> alertsystem.v1.Alert
+	1, // 1: alertsystem.v1.GetActiveKeysResponse.keys:type_name -> alertsystem.v1.PublicKey
+	0, // 2: alertsystem.v1.GetHealthResponse.alert:type_name -> alertsystem.v1.Alert
+	2, // 3: alertsystem.v1.AlertSystem.GetAlert:input_type -> alertsystem.v1.GetAlertRequest
+	3, // 4: alertsystem.v1.AlertSystem.ListAlerts:input_type -> alertsystem.v1.ListAlertsRequest
+	5, // 5: alertsystem.v1.AlertSystem.GetActiveKeys:input_type -> alertsystem.v1.GetActiveKeysRequest
+	7, // 6: alertsystem.v1.AlertSystem.GetHealth:input_type -> alertsystem.v1.GetHealthRequest
+	9, // 7: alertsystem.v1.AlertSystem.WatchAlerts:input_type -> alertsystem.v1.WatchAlertsRequest
+	0, // 8: alertsystem.v1.AlertSystem.GetAlert:output_type -> alertsystem.v1.Alert
+	4, // 9: alertsystem.v1.AlertSystem.ListAlerts:output_type -> alertsystem.v1.ListAlertsResponse
+	6, // 10: alertsystem.v1.AlertSystem.GetActiveKeys:output_type -> alertsystem.v1.GetActiveKeysResponse
+	8, // 11: alertsystem.v1.AlertSystem.GetHealth:output_type -> alertsystem.v1.GetHealthResponse
+	0, // 12: alertsystem.v1.AlertSystem.WatchAlerts:output_type -> alertsystem.v1.Alert
+	8, // [8:13] is the sub-list for method output_type
+	3, // [3:8] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_alert_system_proto_init() }
+func file_alert_system_proto_init() {
+	if File_alert_system_proto != nil {
+		return
+	}
+	file_alert_system_proto_msgTypes[3].OneofWrappers = []any{}
+	file_alert_system_proto_msgTypes[9].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_alert_system_proto_rawDesc), len(file_alert_system_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_alert_system_proto_goTypes,
+		DependencyIndexes: file_alert_system_proto_depIdxs,
+		MessageInfos:      file_alert_system_proto_msgTypes,
+	}.Build()
+	File_alert_system_proto = out.File
+	file_alert_system_proto_goTypes = nil
+	file_alert_system_proto_depIdxs = nil
+}
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: alert_system.proto
+
+package alertpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AlertSystem_GetAlert_FullMethodName      = "/alertsystem.v1.AlertSystem/GetAlert"
+	AlertSystem_ListAlerts_FullMethodName    = "/alertsystem.v1.AlertSystem/ListAlerts"
+	AlertSystem_GetActiveKeys_FullMethodName = "/alertsystem.v1.AlertSystem/GetActiveKeys"
+	AlertSystem_GetHealth_FullMethodName     = "/alertsystem.v1.AlertSystem/GetHealth"
+	AlertSystem_WatchAlerts_FullMethodName   = "/alertsystem.v1.AlertSystem/WatchAlerts"
+)
+
+// AlertSystemClient is the client API for AlertSystem service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AlertSystem exposes the alert history, the active keys and the health of the node
+type AlertSystemClient interface {
+	// GetAlert returns the alert with the given sequence number
+	GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*Alert, error)
+	// ListAlerts returns all alerts (or all alerts after a sequence number)
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	// GetActiveKeys returns the public keys currently used to validate alerts
+	GetActiveKeys(ctx context.Context, in *GetActiveKeysRequest, opts ...grpc.CallOption) (*GetActiveKeysResponse, error)
+	// GetHealth returns the latest alert and the state of the node
+	GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error)
+	// WatchAlerts streams alerts as they are accepted, resuming after a sequence number if set
+	WatchAlerts(ctx context.Context, in *WatchAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error)
+}
+
+type alertSystemClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlertSystemClient(cc grpc.ClientConnInterface) AlertSystemClient {
+	return &alertSystemClient{cc}
+}
+
+func (c *alertSystemClient) GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*Alert, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Alert)
+	err := c.cc.Invoke(ctx, AlertSystem_GetAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertSystemClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, AlertSystem_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertSystemClient) GetActiveKeys(ctx context.Context, in *GetActiveKeysRequest, opts ...grpc.CallOption) (*GetActiveKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActiveKeysResponse)
+	err := c.cc.Invoke(ctx, AlertSystem_GetActiveKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertSystemClient) GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHealthResponse)
+	err := c.cc.Invoke(ctx, AlertSystem_GetHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertSystemClient) WatchAlerts(ctx context.Context, in *WatchAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Alert], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AlertSystem_ServiceDesc.Streams[0], AlertSystem_WatchAlerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAlertsRequest, Alert]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AlertSystem_WatchAlertsClient = grpc.ServerStreamingClient[Alert]
+
+// AlertSystemServer is the server API for AlertSystem service.
+// All implementations must embed UnimplementedAlertSystemServer
+// for forward compatibility.
+//
+// AlertSystem exposes the alert history, the active keys and the health of the node
+type AlertSystemServer interface {
+	// GetAlert returns the alert with the given sequence number
+	GetAlert(context.Context, *GetAlertRequest) (*Alert, error)
+	// ListAlerts returns all alerts (or all alerts after a sequence number)
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	// GetActiveKeys returns the public keys currently used to validate alerts
+	GetActiveKeys(context.Context, *GetActiveKeysRequest) (*GetActiveKeysResponse, error)
+	// GetHealth returns the latest alert and the state of the node
+	GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error)
+	// WatchAlerts streams alerts as they are accepted, resuming after a sequence number if set
+	WatchAlerts(*WatchAlertsRequest, grpc.ServerStreamingServer[Alert]) error
+	mustEmbedUnimplementedAlertSystemServer()
+}
+
+// UnimplementedAlertSystemServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAlertSystemServer struct{}
+
+func (UnimplementedAlertSystemServer) GetAlert(context.Context, *GetAlertRequest) (*Alert, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlert not implemented")
+}
+func (UnimplementedAlertSystemServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedAlertSystemServer) GetActiveKeys(context.Context, *GetActiveKeysRequest) (*GetActiveKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActiveKeys not implemented")
+}
+func (UnimplementedAlertSystemServer) GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHealth not implemented")
+}
+func (UnimplementedAlertSystemServer) WatchAlerts(*WatchAlertsRequest, grpc.ServerStreamingServer[Alert]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAlerts not implemented")
+}
+func (UnimplementedAlertSystemServer) mustEmbedUnimplementedAlertSystemServer() {}
+func (UnimplementedAlertSystemServer) testEmbeddedByValue()                     {}
+
+// UnsafeAlertSystemServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlertSystemServer will
+// result in compilation errors.
+type UnsafeAlertSystemServer interface {
+	mustEmbedUnimplementedAlertSystemServer()
+}
+
+func RegisterAlertSystemServer(s grpc.ServiceRegistrar, srv AlertSystemServer) {
+	// If the following call pancis, it indicates UnimplementedAlertSystemServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AlertSystem_ServiceDesc, srv)
+}
+
+func _AlertSystem_GetAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertSystemServer).GetAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertSystem_GetAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertSystemServer).GetAlert(ctx, req.(*GetAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertSystem_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertSystemServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertSystem_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertSystemServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertSystem_GetActiveKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActiveKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertSystemServer).GetActiveKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertSystem_GetActiveKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertSystemServer).GetActiveKeys(ctx, req.(*GetActiveKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertSystem_GetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertSystemServer).GetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertSystem_GetHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertSystemServer).GetHealth(ctx, req.(*GetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertSystem_WatchAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAlertsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AlertSystemServer).WatchAlerts(m, &grpc.GenericServerStream[WatchAlertsRequest, Alert]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AlertSystem_WatchAlertsServer = grpc.ServerStreamingServer[Alert]
+
+// AlertSystem_ServiceDesc is the grpc.ServiceDesc for AlertSystem service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AlertSystem_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "alertsystem.v1.AlertSystem",
+	HandlerType: (*AlertSystemServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAlert",
+			Handler:    _AlertSystem_GetAlert_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _AlertSystem_ListAlerts_Handler,
+		},
+		{
+			MethodName: "GetActiveKeys",
+			Handler:    _AlertSystem_GetActiveKeys_Handler,
+		},
+		{
+			MethodName: "GetHealth",
+			Handler:    _AlertSystem_GetHealth_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAlerts",
+			Handler:       _AlertSystem_WatchAlerts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "alert_system.proto",
+}
@@ -0,0 +1,4 @@
+// Package alertpb is the generated code for the alert-system gRPC API (see alert_system.proto)
+package alertpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative alert_system.proto
@@ -0,0 +1,234 @@
+// Package grpcserver is the gRPC server for the alert-system
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/events"
+	"github.com/bitcoin-sv/alert-system/app/grpcserver/alertpb"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	p2palert "github.com/bitcoin-sv/alert-system/app/p2p"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Server is the configuration, services, and actual gRPC server
+type Server struct {
+	alertpb.UnimplementedAlertSystemServer
+
+	Config     *config.Config
+	Events     *events.Broadcaster
+	GRPCServer *grpc.Server
+	P2pServer  *p2palert.Server
+}
+
+// NewServer will return a new gRPC server service
+func NewServer(conf *config.Config, serv *p2palert.Server) *Server {
+	s := &Server{
+		Config:    conf,
+		P2pServer: serv,
+	}
+	if serv != nil {
+		s.Events = serv.Events()
+	}
+	return s
+}
+
+// Register will create the gRPC server and register the services (including reflection)
+func (s *Server) Register() *grpc.Server {
+	s.GRPCServer = grpc.NewServer()
+	alertpb.RegisterAlertSystemServer(s.GRPCServer, s)
+	reflection.Register(s.GRPCServer)
+	return s.GRPCServer
+}
+
+// Serve will load a server and start serving
+func (s *Server) Serve() {
+	listener, err := net.Listen("tcp", ":"+s.Config.GRPC.Port)
+	if err != nil {
+		s.Config.Services.Log.Errorf("failed to start gRPC server: %s", err.Error())
+		return
+	}
+	if err = s.Register().Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		s.Config.Services.Log.Info("shutting down gRPC server [" + err.Error() + "]...")
+	}
+}
+
+// Shutdown will stop the gRPC server, waiting for calls to finish until the context is done
+//
+// Open WatchAlerts streams are cancelled when the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.GRPCServer == nil {
+		return nil
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.GRPCServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.GRPCServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// GetAlert will return the alert with the given sequence number
+func (s *Server) GetAlert(ctx context.Context, req *alertpb.GetAlertRequest) (*alertpb.Alert, error) {
+	alert, err := models.GetAlertMessageBySequenceNumber(ctx, req.GetSequence(), model.WithAllDependencies(s.Config))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	} else if alert == nil {
+		return nil, status.Error(codes.NotFound, "alert not found")
+	}
+	return s.toAlert(ctx, alert)
+}
+
+// ListAlerts will return all alerts, or all alerts after a sequence number
+func (s *Server) ListAlerts(ctx context.Context, req *alertpb.ListAlertsRequest) (*alertpb.ListAlertsResponse, error) {
+	var alerts []*models.AlertMessage
+	var err error
+	if req.AfterSequence != nil {
+		alerts, err = models.GetAlertsAfterSequence(ctx, req.GetAfterSequence(), nil, model.WithAllDependencies(s.Config))
+	} else {
+		alerts, err = models.GetAllAlerts(ctx, nil, model.WithAllDependencies(s.Config))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &alertpb.ListAlertsResponse{Alerts: make([]*alertpb.Alert, 0, len(alerts))}
+	for _, alert := range alerts {
+		var a *alertpb.Alert
+		if a, err = s.toAlert(ctx, alert); err != nil {
+			return nil, err
+		}
+		resp.Alerts = append(resp.Alerts, a)
+		resp.LatestSequence = alert.SequenceNumber
+	}
+	return resp, nil
+}
+
+// GetActiveKeys will return the public keys currently used to validate alerts
+func (s *Server) GetActiveKeys(ctx context.Context, _ *alertpb.GetActiveKeysRequest) (*alertpb.GetActiveKeysResponse, error) {
+	keys, err := models.GetActivePublicKey(ctx, nil, model.WithAllDependencies(s.Config))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &alertpb.GetActiveKeysResponse{Keys: make([]*alertpb.PublicKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &alertpb.PublicKey{
+			Key:            key.Key,
+			LastUpdateHash: key.LastUpdateHash,
+		})
+	}
+	return resp, nil
+}
+
+// GetHealth will return the latest alert and the state of the node
+func (s *Server) GetHealth(ctx context.Context, _ *alertpb.GetHealthRequest) (*alertpb.GetHealthResponse, error) {
+	alert, err := models.GetLatestAlert(ctx, nil, model.WithAllDependencies(s.Config))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	} else if alert == nil {
+		return nil, status.Error(codes.NotFound, "alert not found")
+	}
+
+	resp := &alertpb.GetHealthResponse{
+		Sequence: alert.SequenceNumber,
+	}
+	if resp.Alert, err = s.toAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+	if s.P2pServer != nil {
+		resp.ActivePeers = int32(s.P2pServer.ActivePeers()) //nolint:gosec // peer count is small
+		resp.Synced = s.P2pServer.Synced()
+	}
+	failed, _ := models.GetAllUnprocessedAlerts(ctx, nil, model.WithAllDependencies(s.Config))
+	resp.UnprocessedAlerts = int32(len(failed)) //nolint:gosec // alert count is small
+	return resp, nil
+}
+
+// WatchAlerts will stream alerts as they are accepted from gossip or sync
+//
+// If after_sequence is set, every saved alert after it is sent first. A client that
+// falls too far behind is disconnected (Unavailable) and should resume from the last
+// sequence it received.
+func (s *Server) WatchAlerts(req *alertpb.WatchAlertsRequest, stream grpc.ServerStreamingServer[alertpb.Alert]) error {
+	if s.Events == nil {
+		return status.Error(codes.Unavailable, "alert stream is not available")
+	}
+
+	// Subscribe before reading the datastore so nothing is missed in between
+	alerts, unsubscribe := s.Events.Subscribe(events.DefaultSubscriberBuffer)
+	defer unsubscribe()
+
+	// Send what the client missed
+	lastSequence, resume := req.GetAfterSequence(), req.AfterSequence != nil
+	if resume {
+		missed, err := events.GetAlertEventsAfterSequence(stream.Context(), s.Config, lastSequence)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		for _, event := range missed {
+			if err = stream.Send(toAlertFromEvent(event)); err != nil {
+				return err
+			}
+			lastSequence = event.Sequence
+		}
+	}
+
+	// Send new alerts
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-alerts:
+			if !ok {
+				return status.Error(codes.Unavailable, "fell behind the alert stream, resume from the last sequence")
+			}
+			if resume && event.Sequence <= lastSequence {
+				continue
+			}
+			if err := stream.Send(toAlertFromEvent(event)); err != nil {
+				return err
+			}
+			lastSequence, resume = event.Sequence, true
+		}
+	}
+}
+
+// toAlert will convert a saved alert into the gRPC message
+func (s *Server) toAlert(ctx context.Context, alert *models.AlertMessage) (*alertpb.Alert, error) {
+	alert.SetOptions(model.WithAllDependencies(s.Config))
+	if err := alert.ReadRaw(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read alert %d: %s", alert.SequenceNumber, err.Error())
+	}
+	event, err := events.NewAlertEvent(ctx, alert)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return toAlertFromEvent(event), nil
+}
+
+// toAlertFromEvent will convert an alert event into the gRPC message
+func toAlertFromEvent(event *events.AlertEvent) *alertpb.Alert {
+	return &alertpb.Alert{
+		Sequence:      event.Sequence,
+		AlertType:     uint32(event.AlertType),
+		AlertTypeName: event.AlertTypeName,
+		Hash:          event.Hash,
+		Processed:     event.Processed,
+		Raw:           event.Raw,
+		Decoded:       string(event.Alert),
+		Message:       event.Message,
+	}
+}
@@ -0,0 +1,96 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/events"
+	"github.com/bitcoin-sv/alert-system/app/grpcserver/alertpb"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient will serve the server in memory and return a client
+func newTestClient(t *testing.T, s *Server) alertpb.AlertSystemClient {
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = s.Register().Serve(listener)
+	}()
+	t.Cleanup(func() {
+		_ = s.Shutdown(context.Background())
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return alertpb.NewAlertSystemClient(conn)
+}
+
+// TestServer_WatchAlerts will test the method WatchAlerts()
+func TestServer_WatchAlerts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not available", func(t *testing.T) {
+		client := newTestClient(t, NewServer(&config.Config{}, nil))
+
+		stream, err := client.WatchAlerts(context.Background(), &alertpb.WatchAlertsRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.Error(t, err)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("new alerts", func(t *testing.T) {
+		s := NewServer(&config.Config{}, nil)
+		s.Events = events.NewBroadcaster()
+		client := newTestClient(t, s)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stream, err := client.WatchAlerts(ctx, &alertpb.WatchAlertsRequest{})
+		require.NoError(t, err)
+
+		// Wait for the subscription before publishing
+		require.Eventually(t, func() bool {
+			return s.Events.Subscribers() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		s.Events.Publish(&events.AlertEvent{
+			Alert:         []byte(`{"message":"hello"}`),
+			AlertType:     models.AlertTypeInformational,
+			AlertTypeName: models.AlertTypeInformational.Name(),
+			Hash:          "abc",
+			Message:       "Informational: hello",
+			Processed:     true,
+			Raw:           "0102",
+			Sequence:      3,
+		})
+
+		var alert *alertpb.Alert
+		alert, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), alert.GetSequence())
+		assert.Equal(t, uint32(models.AlertTypeInformational), alert.GetAlertType())
+		assert.Equal(t, "Informational", alert.GetAlertTypeName())
+		assert.Equal(t, "abc", alert.GetHash())
+		assert.Equal(t, "Informational: hello", alert.GetMessage())
+		assert.Equal(t, `{"message":"hello"}`, alert.GetDecoded())
+		assert.Equal(t, "0102", alert.GetRaw())
+		assert.True(t, alert.GetProcessed())
+	})
+}
@@ -0,0 +1,60 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// Export will write the alert history and the active public keys to w, returning the number of alerts
+func Export(ctx context.Context, conf *config.Config, w io.Writer) (int, error) {
+	alerts, err := models.GetAllAlerts(ctx, nil, model.WithAllDependencies(conf))
+	if err != nil {
+		return 0, err
+	}
+	var keys []*models.PublicKey
+	if keys, err = models.GetActivePublicKey(ctx, nil, model.WithAllDependencies(conf)); err != nil {
+		return 0, err
+	}
+
+	// Write the header
+	enc := json.NewEncoder(w)
+	header := &Header{
+		ExportedAt: time.Now().UTC(),
+		Format:     Format,
+		Version:    Version,
+	}
+	if len(alerts) > 0 {
+		header.LatestSequence = alerts[len(alerts)-1].SequenceNumber
+	}
+	if err = enc.Encode(&Record{Header: header}); err != nil {
+		return 0, err
+	}
+
+	// Write the alerts (in sequence order)
+	for _, alert := range alerts {
+		if err = enc.Encode(&Record{Alert: &AlertRecord{
+			Hash:     alert.Hash,
+			Raw:      alert.Raw,
+			Sequence: alert.SequenceNumber,
+		}}); err != nil {
+			return 0, err
+		}
+	}
+
+	// Write the public key state
+	for _, key := range keys {
+		if err = enc.Encode(&Record{PublicKey: &PublicKeyRecord{
+			Key:            key.Key,
+			LastUpdateHash: key.LastUpdateHash,
+		}}); err != nil {
+			return 0, err
+		}
+	}
+	return len(alerts), nil
+}
@@ -0,0 +1,56 @@
+// Package history exports, imports and verifies the alert history (the chain of alerts from genesis)
+//
+// The export is a JSON lines file: a header, every alert in sequence order (raw signed bytes)
+// and the active public keys. It is used to move the chain between datastores and to
+// bootstrap nodes that cannot sync from peers.
+package history
+
+import (
+	"errors"
+	"time"
+)
+
+// Export file format
+const (
+	Format  = "alert-system-history" // Format is the format name in the header
+	Version = 1                      // Version is the current format version
+)
+
+// Errors returned by the export and import
+var (
+	ErrConflict          = errors.New("alert conflicts with the saved alert")
+	ErrHashMismatch      = errors.New("alert hash does not match the raw alert")
+	ErrInvalidFormat     = errors.New("not an alert-system history file")
+	ErrInvalidSignatures = errors.New("alert signatures are not valid")
+	ErrKeyStateMismatch  = errors.New("active public keys do not match the exported keys")
+	ErrSequenceGap       = errors.New("alert sequence is not continuous")
+	ErrUnsupportedFormat = errors.New("history file version is not supported")
+)
+
+// Record is a single line of the export file (exactly one field is set)
+type Record struct {
+	Alert     *AlertRecord     `json:"alert,omitempty"`
+	Header    *Header          `json:"header,omitempty"`
+	PublicKey *PublicKeyRecord `json:"public_key,omitempty"`
+}
+
+// Header is the first record of the export file
+type Header struct {
+	ExportedAt     time.Time `json:"exported_at"`
+	Format         string    `json:"format"`
+	LatestSequence uint32    `json:"latest_sequence"`
+	Version        int       `json:"version"`
+}
+
+// AlertRecord is an exported alert
+type AlertRecord struct {
+	Hash     string `json:"hash"`
+	Raw      string `json:"raw"` // Raw signed alert (hex)
+	Sequence uint32 `json:"sequence"`
+}
+
+// PublicKeyRecord is an exported active public key
+type PublicKeyRecord struct {
+	Key            string `json:"key"`
+	LastUpdateHash string `json:"last_update_hash"`
+}
@@ -0,0 +1,181 @@
+package history
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// ImportResult is the outcome of an import
+type ImportResult struct {
+	Imported       int    // Alerts saved into the datastore
+	LatestSequence uint32 // Latest sequence in the datastore after the import
+	Skipped        int    // Alerts that were already in the datastore
+}
+
+// Import will read an export file and save the alerts that are not in the datastore yet
+//
+// The file must start at genesis and be continuous. Alerts already in the datastore must
+// have the same hash, new alerts must be signed by the keys active at that point (set keys
+// alerts are applied as they are imported). Other alerts are saved as unprocessed, so the
+// alert processing of the running server applies them to the node. If the file has public
+// keys, they must match the active keys after the import.
+//
+// The genesis alert must exist (see models.CreateGenesisAlert).
+func Import(ctx context.Context, conf *config.Config, r io.Reader) (*ImportResult, error) {
+	dec := json.NewDecoder(r)
+
+	// Read the header
+	var record Record
+	if err := dec.Decode(&record); err != nil || record.Header == nil || record.Header.Format != Format {
+		return nil, ErrInvalidFormat
+	} else if record.Header.Version != Version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedFormat, record.Header.Version)
+	}
+	header := record.Header
+
+	// Get the latest saved alert
+	latest, err := models.GetLatestAlert(ctx, nil, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	} else if latest == nil {
+		return nil, errors.New("genesis alert not found")
+	}
+
+	// Read the alerts and the public keys
+	result := &ImportResult{LatestSequence: latest.SequenceNumber}
+	var keys []string
+	var next uint32
+	for {
+		record = Record{}
+		if err = dec.Decode(&record); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return result, err
+		}
+
+		if record.PublicKey != nil {
+			keys = append(keys, record.PublicKey.Key)
+			continue
+		} else if record.Alert == nil {
+			return result, ErrInvalidFormat
+		}
+
+		// Check the sequence
+		if record.Alert.Sequence != next {
+			return result, fmt.Errorf("%w: expected sequence %d, got %d", ErrSequenceGap, next, record.Alert.Sequence)
+		}
+		next++
+
+		// Skip alerts that are already saved (they must be the same alert)
+		if record.Alert.Sequence <= latest.SequenceNumber {
+			var existing *models.AlertMessage
+			if existing, err = models.GetAlertMessageBySequenceNumber(
+				ctx, record.Alert.Sequence, model.WithAllDependencies(conf),
+			); err != nil {
+				return result, err
+			} else if existing != nil {
+				if existing.Hash != record.Alert.Hash {
+					return result, fmt.Errorf("%w: sequence %d", ErrConflict, record.Alert.Sequence)
+				}
+				result.Skipped++
+				continue
+			}
+		}
+
+		// Verify and save the alert
+		if err = importAlert(ctx, conf, record.Alert); err != nil {
+			return result, fmt.Errorf("sequence %d: %w", record.Alert.Sequence, err)
+		}
+		result.Imported++
+		result.LatestSequence = record.Alert.Sequence
+	}
+
+	// The file must hold the whole history
+	if next == 0 || next-1 != header.LatestSequence {
+		return result, fmt.Errorf(
+			"%w: the file ends before sequence %d", ErrSequenceGap, header.LatestSequence,
+		)
+	}
+
+	// Check the public key state
+	if len(keys) > 0 {
+		var active []*models.PublicKey
+		if active, err = models.GetActivePublicKey(ctx, nil, model.WithAllDependencies(conf)); err != nil {
+			return result, err
+		}
+		if !sameKeys(keys, active) {
+			return result, ErrKeyStateMismatch
+		}
+	}
+	return result, nil
+}
+
+// importAlert will verify an alert against the active keys and save it
+func importAlert(ctx context.Context, conf *config.Config, record *AlertRecord) error {
+	raw, err := hex.DecodeString(record.Raw)
+	if err != nil {
+		return err
+	}
+	var alert *models.AlertMessage
+	if alert, err = models.NewAlertFromBytes(raw, model.WithAllDependencies(conf)); err != nil {
+		return err
+	} else if alert.SequenceNumber != record.Sequence {
+		return fmt.Errorf("%w: the raw alert has sequence %d", ErrSequenceGap, alert.SequenceNumber)
+	} else if alert.Hash != record.Hash {
+		return ErrHashMismatch
+	}
+
+	// Verify the signatures against the keys active at this point
+	var valid bool
+	if valid, err = alert.AreSignaturesValid(ctx); err != nil {
+		return err
+	} else if !valid {
+		return ErrInvalidSignatures
+	}
+
+	// Read the alert message
+	am := alert.ProcessAlertMessage()
+	if am == nil {
+		return fmt.Errorf("alert type [%d] is not supported", alert.GetAlertType())
+	}
+	if err = am.Read(alert.GetRawMessage()); err != nil {
+		return err
+	}
+
+	// Set keys alerts are needed to verify the rest of the chain (the keys are saved with
+	// the alert), everything else is left for the alert processing of the server
+	if setKeys, ok := am.(models.AlertMessageTxInterface); ok {
+		var changes []models.TxModel
+		if changes, err = setKeys.Changes(ctx); err != nil {
+			return err
+		}
+		alert.Processed = true
+		return models.SaveWithTx(ctx, alert.Datastore(), append(changes, alert)...)
+	}
+	return alert.Save(ctx)
+}
+
+// sameKeys returns true if the exported keys are the active keys
+func sameKeys(keys []string, active []*models.PublicKey) bool {
+	if len(keys) != len(active) {
+		return false
+	}
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	for _, key := range active {
+		if !set[key.Key] {
+			return false
+		}
+	}
+	return true
+}
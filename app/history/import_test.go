@@ -0,0 +1,139 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSignedAlert will create an informational alert signed with the test genesis keys
+func newSignedAlert(t *testing.T, conf *config.Config, sequence uint32, message string) *models.AlertMessage {
+	alert := models.NewAlertMessage(model.WithAllDependencies(conf), model.New())
+	alert.SetAlertType(models.AlertTypeInformational)
+	alert.SetVersion(1)
+	alert.SetTimestamp(uint64(time.Now().Unix()))
+	alert.SetRawMessage(append([]byte{byte(len(message))}, message...))
+	alert.SequenceNumber = sequence
+	alert.SerializeData()
+
+	sigs, err := utils.SignWithKeys(alert.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key3})
+	require.NoError(t, err)
+	alert.SetSignatures(sigs)
+	_ = alert.Serialize()
+	return alert
+}
+
+// TestImport_InvalidFile will test the method Import() with files that are not exports
+func TestImport_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := Import(context.Background(), nil, strings.NewReader(""))
+		require.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		_, err := Import(context.Background(), nil, strings.NewReader(`{"alert":{"sequence":0}}`))
+		require.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("other format", func(t *testing.T) {
+		_, err := Import(context.Background(), nil, strings.NewReader(`{"header":{"format":"other","version":1}}`))
+		require.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		_, err := Import(context.Background(), nil, strings.NewReader(`{"header":{"format":"alert-system-history","version":2}}`))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+// TestSameKeys will test the method sameKeys()
+func TestSameKeys(t *testing.T) {
+	t.Parallel()
+
+	active := []*models.PublicKey{{Key: "a"}, {Key: "b"}}
+	assert.True(t, sameKeys([]string{"b", "a"}, active))
+	assert.False(t, sameKeys([]string{"a"}, active))
+	assert.False(t, sameKeys([]string{"a", "c"}, active))
+}
+
+// newDatastore will replace the datastore with an empty one (with the genesis alert)
+func (ts *TestSuite) newDatastore() {
+	ts.TearDownTest()
+	ts.SetupTest()
+	ts.Require().NoError(models.CreateGenesisAlert(context.Background(), model.WithAllDependencies(ts.Dependencies)))
+}
+
+// TestExportImport will test exporting the history and importing it into a new datastore
+func (ts *TestSuite) TestExportImport() {
+	ctx := context.Background()
+	ts.Require().NoError(models.CreateGenesisAlert(ctx, model.WithAllDependencies(ts.Dependencies)))
+	for i := uint32(1); i <= 2; i++ {
+		alert := newSignedAlert(ts.T(), ts.Dependencies, i, "test")
+		alert.Processed = true
+		ts.Require().NoError(alert.Save(ctx))
+	}
+
+	// Export
+	var buf bytes.Buffer
+	count, err := Export(ctx, ts.Dependencies, &buf)
+	ts.Require().NoError(err)
+	ts.Equal(3, count)
+	export := buf.String()
+
+	ts.Run("import into a new datastore", func() {
+		ts.newDatastore()
+
+		result, importErr := Import(ctx, ts.Dependencies, strings.NewReader(export))
+		ts.Require().NoError(importErr)
+		ts.Equal(2, result.Imported)
+		ts.Equal(1, result.Skipped)
+		ts.Equal(uint32(2), result.LatestSequence)
+
+		// Imported alerts are left for the alert processing
+		unprocessed, getErr := models.GetAllUnprocessedAlerts(ctx, nil, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(getErr)
+		ts.Len(unprocessed, 2)
+
+		// Importing again skips everything
+		result, importErr = Import(ctx, ts.Dependencies, strings.NewReader(export))
+		ts.Require().NoError(importErr)
+		ts.Equal(0, result.Imported)
+		ts.Equal(3, result.Skipped)
+	})
+
+	ts.Run("sequence gap", func() {
+		ts.newDatastore()
+
+		lines := strings.Split(strings.TrimSpace(export), "\n")
+		gap := strings.Join(append([]string{lines[0], lines[1]}, lines[3:]...), "\n")
+		_, importErr := Import(ctx, ts.Dependencies, strings.NewReader(gap))
+		ts.Require().ErrorIs(importErr, ErrSequenceGap)
+	})
+
+	ts.Run("invalid signatures", func() {
+		ts.newDatastore()
+
+		// Corrupt the last signature of the alert
+		alert := newSignedAlert(ts.T(), ts.Dependencies, 1, "test")
+		raw, decodeErr := hex.DecodeString(alert.Raw)
+		ts.Require().NoError(decodeErr)
+		raw[len(raw)-1] ^= 0x01
+		lines := strings.Split(strings.TrimSpace(export), "\n")
+		file := lines[0] + "\n" + lines[1] + "\n" +
+			`{"alert":{"hash":"` + alert.Hash + `","raw":"` + hex.EncodeToString(raw) + `","sequence":1}}`
+		_, importErr := Import(ctx, ts.Dependencies, strings.NewReader(file))
+		ts.Require().ErrorIs(importErr, ErrInvalidSignatures)
+	})
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// Inconsistency is a problem found in the saved alert history
+type Inconsistency struct {
+	Err      error  // The problem (e.g. ErrInvalidSignatures)
+	Sequence uint32 // Sequence of the alert with the problem
+}
+
+// Error returns the problem with its sequence
+func (i *Inconsistency) Error() string {
+	return fmt.Sprintf("sequence %d: %s", i.Sequence, i.Err.Error())
+}
+
+// Unwrap returns the problem
+func (i *Inconsistency) Unwrap() error {
+	return i.Err
+}
+
+// VerifyResult is the outcome of a verification
+type VerifyResult struct {
+	Alerts         int            // Alerts verified (before the inconsistency, if any)
+	Inconsistency  *Inconsistency // The first inconsistency found (nil if the history is valid)
+	LatestSequence uint32         // Latest valid sequence
+}
+
+// Verify will replay the saved alert history from genesis and report the first inconsistency
+//
+// The key set starts with the genesis keys and is replaced by every set keys alert, each
+// alert must be signed by the keys active at its sequence. The stored hash and sequence of
+// each alert must match its raw bytes, and the active public keys must be the keys set by
+// the last set keys alert. Nothing is written to the datastore.
+func Verify(ctx context.Context, conf *config.Config) (*VerifyResult, error) {
+	alerts, err := models.GetAllAlerts(ctx, nil, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{}
+	if len(alerts) == 0 {
+		result.Inconsistency = &Inconsistency{Err: fmt.Errorf("%w: genesis alert not found", ErrSequenceGap)}
+		return result, nil
+	}
+
+	// Check the genesis alert (its keys come from the configuration)
+	if alerts[0].SequenceNumber != 0 {
+		result.Inconsistency = &Inconsistency{Err: fmt.Errorf("%w: genesis alert not found", ErrSequenceGap)}
+		return result, nil
+	} else if alerts[0].Hash != models.GenesisAlertHash() {
+		result.Inconsistency = &Inconsistency{Err: ErrHashMismatch}
+		return result, nil
+	}
+	keys := conf.GenesisKeys
+	keysSetBy := uint32(0)
+	result.Alerts = 1
+
+	// Replay the rest of the chain
+	for _, saved := range alerts[1:] {
+		expected := result.LatestSequence + 1
+		if saved.SequenceNumber != expected {
+			result.Inconsistency = &Inconsistency{
+				Err:      fmt.Errorf("%w: expected sequence %d", ErrSequenceGap, expected),
+				Sequence: saved.SequenceNumber,
+			}
+			return result, nil
+		}
+
+		var newKeys []string
+		if newKeys, err = verifyAlert(conf, saved, keys); err != nil {
+			result.Inconsistency = &Inconsistency{Err: err, Sequence: saved.SequenceNumber}
+			return result, nil
+		} else if newKeys != nil {
+			keys, keysSetBy = newKeys, saved.SequenceNumber
+		}
+		result.Alerts++
+		result.LatestSequence = saved.SequenceNumber
+	}
+
+	// The active keys must be the keys set by the last set keys alert
+	var active []*models.PublicKey
+	if active, err = models.GetActivePublicKey(ctx, nil, model.WithAllDependencies(conf)); err != nil {
+		return nil, err
+	}
+	if !sameKeys(keys, active) {
+		result.Inconsistency = &Inconsistency{Err: ErrKeyStateMismatch, Sequence: keysSetBy}
+	}
+	return result, nil
+}
+
+// verifyAlert will check a saved alert against its raw bytes and the given key set,
+// returning the new key set if it is a set keys alert
+func verifyAlert(conf *config.Config, saved *models.AlertMessage, keys []string) ([]string, error) {
+	raw, err := hex.DecodeString(saved.Raw)
+	if err != nil {
+		return nil, err
+	}
+	var alert *models.AlertMessage
+	if alert, err = models.NewAlertFromBytes(raw, model.WithAllDependencies(conf)); err != nil {
+		return nil, err
+	} else if alert.SequenceNumber != saved.SequenceNumber {
+		return nil, fmt.Errorf("%w: the raw alert has sequence %d", ErrSequenceGap, alert.SequenceNumber)
+	} else if alert.Hash != saved.Hash {
+		return nil, ErrHashMismatch
+	}
+
+	// Check the signatures against the keys active at this point
+	var valid bool
+	if valid, err = alert.AreSignaturesValidWithKeys(keys); err != nil {
+		return nil, err
+	} else if !valid {
+		return nil, ErrInvalidSignatures
+	}
+
+	// Read the new key set
+	if alert.GetAlertType() != models.AlertTypeSetKeys {
+		return nil, nil
+	}
+	setKeys := &models.AlertMessageSetKeys{}
+	if err = setKeys.Read(alert.GetRawMessage()); err != nil {
+		return nil, err
+	}
+	return setKeys.PublicKeys(), nil
+}
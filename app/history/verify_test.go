@@ -0,0 +1,107 @@
+package history
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInconsistency_Error will test the method Error()
+func TestInconsistency_Error(t *testing.T) {
+	t.Parallel()
+
+	err := &Inconsistency{Err: ErrInvalidSignatures, Sequence: 4}
+	assert.Equal(t, "sequence 4: alert signatures are not valid", err.Error())
+	assert.ErrorIs(t, err, ErrInvalidSignatures)
+}
+
+// TestVerify will test the method Verify()
+func (ts *TestSuite) TestVerify() {
+	ctx := context.Background()
+
+	// saveAlerts will save signed alerts from sequence 1 to n
+	saveAlerts := func(n uint32) []*models.AlertMessage {
+		alerts := make([]*models.AlertMessage, 0, n)
+		for i := uint32(1); i <= n; i++ {
+			alert := newSignedAlert(ts.T(), ts.Dependencies, i, "test")
+			ts.Require().NoError(alert.Save(ctx))
+			alerts = append(alerts, alert)
+		}
+		return alerts
+	}
+
+	ts.Run("valid", func() {
+		ts.newDatastore()
+		saveAlerts(3)
+
+		result, err := Verify(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Nil(result.Inconsistency)
+		ts.Equal(4, result.Alerts)
+		ts.Equal(uint32(3), result.LatestSequence)
+	})
+
+	ts.Run("no genesis", func() {
+		ts.TearDownTest()
+		ts.SetupTest()
+
+		result, err := Verify(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(result.Inconsistency)
+		ts.True(errors.Is(result.Inconsistency, ErrSequenceGap))
+	})
+
+	ts.Run("gap", func() {
+		ts.newDatastore()
+		saveAlerts(1)
+		ts.Require().NoError(newSignedAlert(ts.T(), ts.Dependencies, 3, "test").Save(ctx))
+
+		result, err := Verify(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(result.Inconsistency)
+		ts.True(errors.Is(result.Inconsistency, ErrSequenceGap))
+		ts.Equal(uint32(3), result.Inconsistency.Sequence)
+		ts.Equal(uint32(1), result.LatestSequence)
+	})
+
+	ts.Run("tampered signature", func() {
+		ts.newDatastore()
+		alerts := saveAlerts(3)
+
+		// Corrupt the last signature of the second alert
+		raw, err := hex.DecodeString(alerts[1].Raw)
+		ts.Require().NoError(err)
+		raw[len(raw)-1] ^= 0x01
+		tampered, err := models.GetAlertMessageBySequenceNumber(ctx, 2, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		tampered.Raw = hex.EncodeToString(raw)
+		ts.Require().NoError(tampered.Save(ctx))
+
+		result, err := Verify(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(result.Inconsistency)
+		ts.True(errors.Is(result.Inconsistency, ErrInvalidSignatures))
+		ts.Equal(uint32(2), result.Inconsistency.Sequence)
+	})
+
+	ts.Run("tampered hash", func() {
+		ts.newDatastore()
+		saveAlerts(2)
+
+		tampered, err := models.GetAlertMessageBySequenceNumber(ctx, 1, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		tampered.Hash = "0000000000000000000000000000000000000000000000000000000000000000"
+		ts.Require().NoError(tampered.Save(ctx))
+
+		result, err := Verify(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(result.Inconsistency)
+		ts.True(errors.Is(result.Inconsistency, ErrHashMismatch))
+		ts.Equal(uint32(1), result.Inconsistency.Sequence)
+	})
+}
@@ -0,0 +1,298 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Condition operators (the same operators as the MongoDB conditions used by the models)
+const (
+	conditionAnd             = "$and"
+	conditionEqual           = "$eq"
+	conditionExists          = "$exists"
+	conditionGreaterThan     = "$gt"
+	conditionGreaterOrEqual  = "$gte"
+	conditionIn              = "$in"
+	conditionLessThan        = "$lt"
+	conditionLessThanOrEqual = "$lte"
+	conditionNotEqual        = "$ne"
+	conditionNotIn           = "$nin"
+	conditionOr              = "$or"
+	conditionOperatorPrefix  = "$"
+)
+
+// normalizeField is the field used to convert a value like it is stored
+const normalizeField = "v"
+
+// ErrUnsupportedCondition is returned when a condition cannot be evaluated
+var ErrUnsupportedCondition = errors.New("unsupported kvstore condition")
+
+// match will return true if the document matches all the conditions
+//
+// A missing field and a null field are the same (IS NULL in SQL)
+func match(doc bson.M, conditions map[string]interface{}) (bool, error) {
+	for key, condition := range conditions {
+		var matched bool
+		var err error
+		switch key {
+		case conditionAnd, conditionOr:
+			var list []map[string]interface{}
+			if list, err = conditionList(condition); err != nil {
+				return false, err
+			}
+			matched = key == conditionAnd
+			for _, c := range list {
+				var m bool
+				if m, err = match(doc, c); err != nil {
+					return false, err
+				} else if m != matched { // First false ($and) or first true ($or)
+					matched = m
+					break
+				}
+			}
+		default:
+			if key == sqlIDField {
+				key = idField
+			}
+			matched, err = matchField(doc[key], condition)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchField will return true if the value of the field matches the condition
+func matchField(value, condition interface{}) (bool, error) {
+
+	// Equality
+	operators, ok := operatorMap(condition)
+	if !ok {
+		return equal(value, condition)
+	}
+
+	// Operators
+	for operator, operand := range operators {
+		var matched bool
+		var err error
+		switch operator {
+		case conditionExists:
+			exists, isBool := operand.(bool)
+			if !isBool {
+				return false, fmt.Errorf("%w: %s must be a bool", ErrUnsupportedCondition, operator)
+			}
+			matched = (value != nil) == exists
+		case conditionEqual:
+			matched, err = equal(value, operand)
+		case conditionNotEqual:
+			matched, err = equal(value, operand)
+			matched = !matched
+		case conditionGreaterThan, conditionGreaterOrEqual, conditionLessThan, conditionLessThanOrEqual:
+			matched, err = compareOperator(operator, value, operand)
+		case conditionIn, conditionNotIn:
+			matched, err = in(value, operand)
+			if operator == conditionNotIn {
+				matched = !matched
+			}
+		default:
+			return false, fmt.Errorf("%w: %s", ErrUnsupportedCondition, operator)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// equal will return true if the document value equals the condition value
+func equal(value, condition interface{}) (bool, error) {
+	normalized, err := normalize(condition)
+	if err != nil {
+		return false, err
+	} else if value == nil || normalized == nil {
+		return value == nil && normalized == nil, nil
+	}
+	result, comparable := compare(value, normalized)
+	return comparable && result == 0, nil
+}
+
+// compareOperator will compare the document value with the condition value ($gt, $gte, $lt or $lte)
+func compareOperator(operator string, value, condition interface{}) (bool, error) {
+	normalized, err := normalize(condition)
+	if err != nil || value == nil || normalized == nil {
+		return false, err
+	}
+	result, comparable := compare(value, normalized)
+	if !comparable {
+		return false, nil
+	}
+	switch operator {
+	case conditionGreaterThan:
+		return result > 0, nil
+	case conditionGreaterOrEqual:
+		return result >= 0, nil
+	case conditionLessThan:
+		return result < 0, nil
+	default:
+		return result <= 0, nil
+	}
+}
+
+// in will return true if the document value equals one of the condition values
+func in(value, condition interface{}) (bool, error) {
+	list := reflect.ValueOf(condition)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return false, fmt.Errorf("%w: %s must be a list", ErrUnsupportedCondition, conditionIn)
+	}
+	for i := 0; i < list.Len(); i++ {
+		if matched, err := equal(value, list.Index(i).Interface()); err != nil || matched {
+			return matched, err
+		}
+	}
+	return false, nil
+}
+
+// less will return true if a sorts before b (null values first)
+func less(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+	result, _ := compare(a, b)
+	return result < 0
+}
+
+// compare will compare two normalized values (-1, 0 or 1) and return false if they cannot be compared
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case int32, int64, float64:
+		if y, ok := number(b); ok {
+			v, _ := number(x)
+			return compareOrdered(v, y), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(boolNumber(x), boolNumber(y)), true
+		}
+	case primitive.DateTime:
+		if y, ok := b.(primitive.DateTime); ok {
+			return compareOrdered(x, y), true
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return 0, true
+	}
+	return 0, false
+}
+
+// compareOrdered will compare two ordered values
+func compareOrdered[T int | int64 | float64 | primitive.DateTime](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// number will return the numeric value as a float64
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// boolNumber will return 1 for true and 0 for false
+func boolNumber(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// normalize will convert the condition value to the type it has in a stored document
+// (uint32 to int64, time.Time to a DateTime, string types to string, etc.)
+func normalize(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := bson.Marshal(bson.M{normalizeField: value})
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err = bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc[normalizeField], nil
+}
+
+// operatorMap will return the operators of the condition (false if the condition is a value)
+func operatorMap(condition interface{}) (map[string]interface{}, bool) {
+	var operators map[string]interface{}
+	switch c := condition.(type) {
+	case map[string]interface{}:
+		operators = c
+	case bson.M:
+		operators = c
+	default:
+		return nil, false
+	}
+	for operator := range operators {
+		if !strings.HasPrefix(operator, conditionOperatorPrefix) {
+			return nil, false
+		}
+	}
+	return operators, len(operators) > 0
+}
+
+// conditionList will return the list of conditions of $and or $or
+func conditionList(condition interface{}) ([]map[string]interface{}, error) {
+	switch c := condition.(type) {
+	case []map[string]interface{}:
+		return c, nil
+	case []bson.M:
+		list := make([]map[string]interface{}, 0, len(c))
+		for _, m := range c {
+			list = append(list, m)
+		}
+		return list, nil
+	case []interface{}:
+		list := make([]map[string]interface{}, 0, len(c))
+		for _, item := range c {
+			m, ok := conditionMap(item)
+			if !ok {
+				return nil, fmt.Errorf("%w: %T in a list of conditions", ErrUnsupportedCondition, item)
+			}
+			list = append(list, m)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("%w: %T is not a list of conditions", ErrUnsupportedCondition, condition)
+}
+
+// conditionMap will return the item of a list of conditions as a map
+func conditionMap(item interface{}) (map[string]interface{}, bool) {
+	switch m := item.(type) {
+	case map[string]interface{}:
+		return m, true
+	case bson.M:
+		return m, true
+	}
+	return nil, false
+}
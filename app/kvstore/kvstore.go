@@ -0,0 +1,222 @@
+// Package kvstore is an embedded key-value datastore (a single bbolt data file)
+//
+// It implements the datastore client used by the models, so the alert system can run as a
+// single binary without an external database. Every table is a bucket of BSON documents keyed
+// by the record ID, and the queries are evaluated in memory (the tables of the alert system are small).
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
+)
+
+// Engine is the datastore engine name of the embedded key-value store
+const Engine datastore.Engine = "kvstore"
+
+// DefaultDatabasePath is the data file used when no path is configured
+const DefaultDatabasePath = "alert_system_datastore.kv"
+
+// openTimeout is how long to wait for the lock of the data file (another process has it open)
+const openTimeout = 5 * time.Second
+
+// ErrMissingDatabasePath is returned when the data file path is empty
+var ErrMissingDatabasePath = errors.New("missing kvstore database path")
+
+// Client is the embedded key-value datastore client
+type Client struct {
+	autoMigrate  bool
+	databasePath string
+	db           *bbolt.DB
+	debug        bool
+	pending      map[*datastore.Transaction][]*write // Writes waiting for the transaction to commit
+	pendingLock  sync.Mutex
+	tablePrefix  string
+}
+
+// Config is the configuration of the embedded key-value datastore
+type Config struct {
+	AutoMigrate  bool   // Reported by IsAutoMigrate() (the buckets are created on the first write)
+	DatabasePath string // Path of the data file (created if it does not exist)
+	Debug        bool   // Log the writes
+	TablePrefix  string // pre_table_name (pre)
+}
+
+// NewClient will open (or create) the data file and return the client
+func NewClient(config *Config) (*Client, error) {
+	if config == nil || len(config.DatabasePath) == 0 {
+		return nil, ErrMissingDatabasePath
+	}
+
+	// Create the directory of the data file if needed
+	if dir := filepath.Dir(config.DatabasePath); len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := bbolt.Open(config.DatabasePath, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		autoMigrate:  config.AutoMigrate,
+		databasePath: config.DatabasePath,
+		db:           db,
+		debug:        config.Debug,
+		pending:      make(map[*datastore.Transaction][]*write),
+		tablePrefix:  config.TablePrefix,
+	}, nil
+}
+
+// Close will close the data file
+func (c *Client) Close(_ context.Context) error {
+	return c.db.Close()
+}
+
+// Debug will turn the debug logging on or off
+func (c *Client) Debug(on bool) {
+	c.debug = on
+}
+
+// DebugLog will log the text if debug is on
+func (c *Client) DebugLog(_ context.Context, text string) {
+	if c.debug {
+		log.Println("kvstore: " + text)
+	}
+}
+
+// Engine will return the engine of the client
+func (c *Client) Engine() datastore.Engine {
+	return Engine
+}
+
+// IsAutoMigrate will return true if the auto migration is enabled
+func (c *Client) IsAutoMigrate() bool {
+	return c.autoMigrate
+}
+
+// IsDebug will return true if debug is on
+func (c *Client) IsDebug() bool {
+	return c.debug
+}
+
+// IsNewRelicEnabled will return false (not supported)
+func (c *Client) IsNewRelicEnabled() bool {
+	return false
+}
+
+// GetArrayFields will return nil (no array fields)
+func (c *Client) GetArrayFields() []string {
+	return nil
+}
+
+// GetDatabaseName will return the path of the data file
+func (c *Client) GetDatabaseName() string {
+	return c.databasePath
+}
+
+// GetMongoCollection will return nil (not MongoDB)
+func (c *Client) GetMongoCollection(_ string) *mongo.Collection {
+	return nil
+}
+
+// GetMongoCollectionByTableName will return nil (not MongoDB)
+func (c *Client) GetMongoCollectionByTableName(_ string) *mongo.Collection {
+	return nil
+}
+
+// GetMongoConditionProcessor will return nil (not MongoDB)
+func (c *Client) GetMongoConditionProcessor() func(conditions *map[string]interface{}) {
+	return nil
+}
+
+// GetMongoIndexer will return nil (not MongoDB)
+func (c *Client) GetMongoIndexer() func() map[string][]mongo.IndexModel {
+	return nil
+}
+
+// GetObjectFields will return nil (no object fields)
+func (c *Client) GetObjectFields() []string {
+	return nil
+}
+
+// GetTableName will return the table (bucket) name of the model with the prefix
+func (c *Client) GetTableName(modelName string) string {
+	if len(c.tablePrefix) > 0 {
+		return c.tablePrefix + "_" + modelName
+	}
+	return modelName
+}
+
+// AutoMigrateDatabase will create the buckets of the models
+func (c *Client) AutoMigrateDatabase(_ context.Context, models ...interface{}) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		for _, m := range models {
+			table, err := c.tableName(m)
+			if err != nil {
+				return err
+			}
+			if _, err = tx.CreateBucketIfNotExists([]byte(table)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HasMigratedModel will return false (the buckets are created on the first write)
+func (c *Client) HasMigratedModel(_ string) bool {
+	return false
+}
+
+// IndexExists will return false (the queries scan the bucket, only the unique fields are indexed to check them)
+func (c *Client) IndexExists(_, _ string) (bool, error) {
+	return false, nil
+}
+
+// IndexMetadata will do nothing (there are no indexes)
+func (c *Client) IndexMetadata(_, _ string) error {
+	return nil
+}
+
+// CreateInBatches is not implemented
+func (c *Client) CreateInBatches(_ context.Context, _ interface{}, _ int) error {
+	return datastore.ErrNotImplemented
+}
+
+// CustomWhere will return nil (not SQL)
+func (c *Client) CustomWhere(_ datastore.CustomWhereInterface, _ map[string]interface{}, _ datastore.Engine) interface{} {
+	return nil
+}
+
+// Execute will return nil (not SQL)
+func (c *Client) Execute(_ string) *gorm.DB {
+	return nil
+}
+
+// Raw will return nil (not SQL)
+func (c *Client) Raw(_ string) *gorm.DB {
+	return nil
+}
+
+// GetModelsAggregate is not implemented
+func (c *Client) GetModelsAggregate(_ context.Context, _ interface{}, _ map[string]interface{},
+	_ string, _ time.Duration) (map[string]interface{}, error) {
+	return nil, datastore.ErrNotImplemented
+}
+
+// IncrementModel is not implemented
+func (c *Client) IncrementModel(_ context.Context, _ interface{}, _ string, _ int64) (int64, error) {
+	return 0, datastore.ErrNotImplemented
+}
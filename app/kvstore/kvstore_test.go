@@ -0,0 +1,297 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// errTestRollback is returned by a transaction function to roll it back
+var errTestRollback = errors.New("rollback")
+
+// testModel is a model stored in the test client
+type testModel struct {
+	ID        uint64     `bson:"_id"`
+	Name      string     `bson:"name"`
+	Sequence  uint32     `bson:"sequence"`
+	Active    bool       `bson:"active"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+}
+
+// GetModelTableName will get the table name of the model
+func (m *testModel) GetModelTableName() string {
+	return "tests"
+}
+
+// testUniqueModel is a model with a unique field (like models.SchemaMigration)
+type testUniqueModel struct {
+	ID      uint64 `bson:"_id"`
+	Name    string `bson:"name"`
+	Version uint32 `bson:"version" gorm:"<-;type:int8;uniqueIndex;comment:This is the version"`
+}
+
+// GetModelTableName will get the table name of the model
+func (m *testUniqueModel) GetModelTableName() string {
+	return "unique_tests"
+}
+
+// newTestClient will create a client with a data file in a temporary directory
+func newTestClient(t *testing.T) *Client {
+	c, err := NewClient(&Config{
+		DatabasePath: filepath.Join(t.TempDir(), "data", "test.kv"),
+		TablePrefix:  "pre",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close(context.Background())
+	})
+	return c
+}
+
+// saveTestModels will save new test models with the sequences 1 to 5 (the even ones are active)
+func saveTestModels(t *testing.T, c *Client) {
+	deleted := time.Now().UTC()
+	for sequence := uint32(1); sequence <= 5; sequence++ {
+		m := &testModel{Name: "model", Sequence: sequence, Active: sequence%2 == 0}
+		if sequence == 5 {
+			m.DeletedAt = &deleted
+		}
+		require.NoError(t, c.SaveModel(context.Background(), m, nil, true, true))
+		assert.Equal(t, uint64(sequence), m.ID)
+	}
+}
+
+// TestNewClient will test the method NewClient()
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing path", func(t *testing.T) {
+		c, err := NewClient(&Config{})
+		require.ErrorIs(t, err, ErrMissingDatabasePath)
+		assert.Nil(t, c)
+	})
+
+	t.Run("valid client", func(t *testing.T) {
+		c := newTestClient(t)
+		assert.Equal(t, Engine, c.Engine())
+		assert.Equal(t, "pre_tests", c.GetTableName("tests"))
+		require.NoError(t, c.AutoMigrateDatabase(context.Background(), &testModel{}))
+	})
+}
+
+// TestClient_GetModel will test the methods SaveModel() and GetModel()
+func TestClient_GetModel(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := newTestClient(t)
+	saveTestModels(t, c)
+
+	t.Run("by id", func(t *testing.T) {
+		m := &testModel{}
+		require.NoError(t, c.GetModel(ctx, m, map[string]interface{}{"id": 3}, 0, false))
+		assert.Equal(t, uint32(3), m.Sequence)
+	})
+
+	t.Run("first match", func(t *testing.T) {
+		m := &testModel{}
+		require.NoError(t, c.GetModel(ctx, m, map[string]interface{}{"active": true}, 0, false))
+		assert.Equal(t, uint32(2), m.Sequence)
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		err := c.GetModel(ctx, &testModel{}, map[string]interface{}{"name": "missing"}, 0, false)
+		require.ErrorIs(t, err, datastore.ErrNoResults)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		m := &testModel{}
+		require.NoError(t, c.GetModel(ctx, m, map[string]interface{}{"sequence": uint32(1)}, 0, false))
+		m.Name = "updated"
+		require.NoError(t, c.SaveModel(ctx, m, nil, false, true))
+
+		updated := &testModel{}
+		require.NoError(t, c.GetModel(ctx, updated, map[string]interface{}{"id": m.ID}, 0, false))
+		assert.Equal(t, "updated", updated.Name)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		err := c.SaveModel(ctx, &testModel{ID: 1}, nil, true, true)
+		require.ErrorIs(t, err, datastore.ErrDuplicateKey)
+	})
+}
+
+// TestClient_GetModels will test the methods GetModels() and GetModelCount()
+func TestClient_GetModels(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := newTestClient(t)
+	saveTestModels(t, c)
+
+	// The conditions like GetModelsByConditions() creates them
+	notDeleted := map[string]interface{}{
+		"$and": []map[string]interface{}{{
+			"deleted_at": map[string]interface{}{"$exists": false},
+			"sequence":   map[string]interface{}{"$gt": uint32(1)},
+		}},
+	}
+
+	t.Run("sorted and paged", func(t *testing.T) {
+		var models []*testModel
+		require.NoError(t, c.GetModels(ctx, &models, notDeleted, &datastore.QueryParams{
+			Page:          1,
+			PageSize:      2,
+			OrderByField:  "sequence",
+			SortDirection: "DESC",
+		}, nil, 0))
+		require.Len(t, models, 2)
+		assert.Equal(t, uint32(4), models[0].Sequence)
+		assert.Equal(t, uint32(3), models[1].Sequence)
+
+		require.NoError(t, c.GetModels(ctx, &models, notDeleted, &datastore.QueryParams{
+			Page:          2,
+			PageSize:      2,
+			OrderByField:  "sequence",
+			SortDirection: "DESC",
+		}, nil, 0))
+		require.Len(t, models, 1)
+		assert.Equal(t, uint32(2), models[0].Sequence)
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		var models []testModel
+		require.NoError(t, c.GetModels(ctx, &models, map[string]interface{}{
+			"$or": []interface{}{
+				map[string]interface{}{"sequence": map[string]interface{}{"$lte": 1}},
+				map[string]interface{}{"sequence": map[string]interface{}{"$in": []uint32{4, 5}}},
+			},
+			"active": map[string]interface{}{"$ne": true},
+		}, nil, nil, 0))
+		require.Len(t, models, 2)
+		assert.Equal(t, uint32(1), models[0].Sequence)
+		assert.Equal(t, uint32(5), models[1].Sequence)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		count, err := c.GetModelCount(ctx, &testModel{}, notDeleted, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("empty table", func(t *testing.T) {
+		models := []*testModel{{}}
+		require.NoError(t, newTestClient(t).GetModels(ctx, &models, nil, nil, nil, 0))
+		assert.Empty(t, models)
+	})
+
+	t.Run("unsupported condition", func(t *testing.T) {
+		var models []*testModel
+		err := c.GetModels(ctx, &models, map[string]interface{}{
+			"name": map[string]interface{}{"$regex": "model"},
+		}, nil, nil, 0)
+		require.ErrorIs(t, err, ErrUnsupportedCondition)
+	})
+}
+
+// TestClient_NewTx will test the method NewTx()
+func TestClient_NewTx(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	t.Run("rollback", func(t *testing.T) {
+		err := c.NewTx(ctx, func(tx *datastore.Transaction) error {
+			require.NoError(t, c.SaveModel(ctx, &testModel{Name: "first"}, tx, true, false))
+			return errTestRollback
+		})
+		require.ErrorIs(t, err, errTestRollback)
+
+		count, countErr := c.GetModelCount(ctx, &testModel{}, nil, 0)
+		require.NoError(t, countErr)
+		assert.Zero(t, count)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		require.NoError(t, c.NewTx(ctx, func(tx *datastore.Transaction) error {
+			for _, name := range []string{"first", "second"} {
+				require.NoError(t, c.SaveModel(ctx, &testModel{Name: name}, tx, true, false))
+			}
+
+			// Not written until the function returns
+			count, err := c.GetModelCount(ctx, &testModel{}, nil, 0)
+			require.NoError(t, err)
+			assert.Zero(t, count)
+			return nil
+		}))
+
+		count, err := c.GetModelCount(ctx, &testModel{}, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
+
+// TestClient_UniqueIndex will test the unique fields of SaveModel() and NewTx()
+func TestClient_UniqueIndex(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("duplicate value", func(t *testing.T) {
+		c := newTestClient(t)
+		require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true))
+		err := c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true)
+		require.ErrorIs(t, err, datastore.ErrDuplicateKey)
+		assert.Contains(t, err.Error(), "version")
+
+		count, countErr := c.GetModelCount(ctx, &testUniqueModel{}, nil, 0)
+		require.NoError(t, countErr)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("update and change the value", func(t *testing.T) {
+		c := newTestClient(t)
+		m := &testUniqueModel{Version: 1}
+		require.NoError(t, c.SaveModel(ctx, m, nil, true, true))
+		m.Name = "updated"
+		require.NoError(t, c.SaveModel(ctx, m, nil, false, true))
+
+		// The previous value can be used by another document
+		m.Version = 2
+		require.NoError(t, c.SaveModel(ctx, m, nil, false, true))
+		require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true))
+		require.ErrorIs(t, c.SaveModel(ctx, &testUniqueModel{Version: 2}, nil, true, true), datastore.ErrDuplicateKey)
+	})
+
+	t.Run("the transaction fails on a duplicate", func(t *testing.T) {
+		c := newTestClient(t)
+		require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true))
+		err := c.NewTx(ctx, func(tx *datastore.Transaction) error {
+			for _, version := range []uint32{2, 3, 2} {
+				require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: version}, tx, true, false))
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, datastore.ErrDuplicateKey)
+
+		// Nothing of the transaction was written
+		count, countErr := c.GetModelCount(ctx, &testUniqueModel{}, nil, 0)
+		require.NoError(t, countErr)
+		assert.Equal(t, int64(1), count)
+		require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: 2}, nil, true, true))
+	})
+
+	t.Run("documents saved before the index", func(t *testing.T) {
+		c := newTestClient(t)
+		require.NoError(t, c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true))
+		require.NoError(t, c.db.Update(func(btx *bbolt.Tx) error {
+			return btx.DeleteBucket(uniqueIndexName(c.GetTableName("unique_tests"), "version"))
+		}))
+		require.ErrorIs(t, c.SaveModel(ctx, &testUniqueModel{Version: 1}, nil, true, true), datastore.ErrDuplicateKey)
+	})
+}
@@ -0,0 +1,414 @@
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-datastore"
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Field names of the stored documents
+const (
+	idField    = "_id" // ID of every document (the key of the bucket)
+	sqlIDField = "id"  // ID field used in the conditions and query params
+)
+
+// Unique indexes (see uniqueFields)
+const (
+	uniqueIndexSeparator = "__unique_"   // Between the table and the field in the name of the index bucket
+	uniqueIndexTag       = "uniqueIndex" // The gorm tag of a field with unique values
+)
+
+// ErrUnknownModel is returned when the table name of a model cannot be found
+var ErrUnknownModel = errors.New("model does not have a table name")
+
+// tableNamer is a model with a table name
+type tableNamer interface {
+	GetModelTableName() string
+}
+
+// write is a document waiting to be written
+type write struct {
+	data      []byte
+	id        uint64
+	newRecord bool
+	table     string
+	unique    []string // Fields with unique values (see uniqueFields)
+}
+
+// document is a stored document
+type document struct {
+	fields bson.M
+	raw    []byte
+}
+
+// SaveModel will save the model into its bucket
+//
+// New models without an ID get the next ID of the bucket. When the transaction was started by NewTx,
+// the write is held until the transaction function returns without an error. A field with a gorm
+// uniqueIndex cannot have the value of another document (datastore.ErrDuplicateKey).
+func (c *Client) SaveModel(_ context.Context, model interface{}, tx *datastore.Transaction, newRecord, _ bool) error {
+	table, err := c.tableName(model)
+	if err != nil {
+		return err
+	}
+
+	// Get (or set) the ID of the model
+	idValue := reflect.Indirect(reflect.ValueOf(model)).FieldByName("ID")
+	if !idValue.IsValid() || idValue.Kind() != reflect.Uint64 {
+		return ErrUnknownModel
+	}
+	if idValue.Uint() == 0 {
+		var id uint64
+		if id, err = c.nextID(table); err != nil {
+			return err
+		}
+		idValue.SetUint(id)
+	}
+
+	// Encode the document
+	w := &write{id: idValue.Uint(), newRecord: newRecord, table: table, unique: uniqueFields(reflect.TypeOf(model))}
+	if w.data, err = bson.Marshal(model); err != nil {
+		return err
+	}
+	c.DebugLog(context.Background(), "saving "+table+" document")
+
+	// Hold the write until the transaction completes
+	if tx != nil {
+		c.pendingLock.Lock()
+		writes, ok := c.pending[tx]
+		if ok {
+			c.pending[tx] = append(writes, w)
+		}
+		c.pendingLock.Unlock()
+		if ok {
+			return nil
+		}
+	}
+
+	return c.db.Update(func(btx *bbolt.Tx) error {
+		return put(btx, w)
+	})
+}
+
+// NewTx will run the function in a transaction, the saved models are written together
+// (in a single write transaction of the data file) when the function returns without an error
+func (c *Client) NewTx(_ context.Context, fn func(*datastore.Transaction) error) error {
+	tx := &datastore.Transaction{}
+
+	c.pendingLock.Lock()
+	c.pending[tx] = make([]*write, 0)
+	c.pendingLock.Unlock()
+
+	err := fn(tx)
+
+	c.pendingLock.Lock()
+	writes := c.pending[tx]
+	delete(c.pending, tx)
+	c.pendingLock.Unlock()
+
+	if err != nil || len(writes) == 0 {
+		return err
+	}
+	return c.db.Update(func(btx *bbolt.Tx) error {
+		for _, w := range writes {
+			if err = put(btx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NewRawTx will return a transaction that writes immediately
+func (c *Client) NewRawTx() (*datastore.Transaction, error) {
+	return &datastore.Transaction{}, nil
+}
+
+// GetModel will get the first model (lowest ID) matching the conditions
+func (c *Client) GetModel(_ context.Context, model interface{}, conditions map[string]interface{},
+	_ time.Duration, _ bool) error {
+	table, err := c.tableName(model)
+	if err != nil {
+		return err
+	}
+
+	var documents []*document
+	if documents, err = c.find(table, conditions, &datastore.QueryParams{Page: 1, PageSize: 1}); err != nil {
+		return err
+	} else if len(documents) == 0 {
+		return datastore.ErrNoResults
+	}
+	return bson.Unmarshal(documents[0].raw, model)
+}
+
+// GetModels will get the models matching the conditions (sorted and paged by the query params)
+func (c *Client) GetModels(_ context.Context, models interface{}, conditions map[string]interface{},
+	queryParams *datastore.QueryParams, fieldResults interface{}, _ time.Duration) error {
+	if fieldResults != nil {
+		return datastore.ErrNotImplemented
+	}
+
+	sliceValue := reflect.ValueOf(models)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		return ErrUnknownModel
+	}
+	sliceValue = sliceValue.Elem()
+
+	table, err := c.tableName(models)
+	if err != nil {
+		return err
+	}
+
+	var documents []*document
+	if documents, err = c.find(table, conditions, queryParams); err != nil {
+		return err
+	}
+
+	// Decode the documents into new items of the slice
+	itemType := sliceValue.Type().Elem()
+	items := reflect.MakeSlice(sliceValue.Type(), 0, len(documents))
+	for _, doc := range documents {
+		var item reflect.Value
+		if itemType.Kind() == reflect.Ptr {
+			item = reflect.New(itemType.Elem())
+		} else {
+			item = reflect.New(itemType)
+		}
+		if err = bson.Unmarshal(doc.raw, item.Interface()); err != nil {
+			return err
+		}
+		if itemType.Kind() != reflect.Ptr {
+			item = item.Elem()
+		}
+		items = reflect.Append(items, item)
+	}
+	sliceValue.Set(items)
+	return nil
+}
+
+// GetModelCount will count the models matching the conditions
+func (c *Client) GetModelCount(_ context.Context, model interface{}, conditions map[string]interface{},
+	_ time.Duration) (int64, error) {
+	table, err := c.tableName(model)
+	if err != nil {
+		return 0, err
+	}
+
+	var documents []*document
+	if documents, err = c.find(table, conditions, nil); err != nil {
+		return 0, err
+	}
+	return int64(len(documents)), nil
+}
+
+// find will return the documents of the table matching the conditions (sorted and paged by the query params)
+func (c *Client) find(table string, conditions map[string]interface{},
+	queryParams *datastore.QueryParams) (documents []*document, err error) {
+
+	if err = c.db.View(func(btx *bbolt.Tx) error {
+		bucket := btx.Bucket([]byte(table))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			doc := &document{raw: append([]byte(nil), v...)}
+			if err := bson.Unmarshal(doc.raw, &doc.fields); err != nil {
+				return err
+			}
+			matched, err := match(doc.fields, conditions)
+			if matched {
+				documents = append(documents, doc)
+			}
+			return err
+		})
+	}); err != nil || queryParams == nil {
+		return
+	}
+
+	// Sort the documents (they are already sorted by ID)
+	if orderBy := queryParams.OrderByField; len(orderBy) > 0 {
+		if orderBy == sqlIDField {
+			orderBy = idField
+		}
+		descending := strings.EqualFold(queryParams.SortDirection, datastore.SortDesc)
+		sort.SliceStable(documents, func(i, j int) bool {
+			if descending {
+				return less(documents[j].fields[orderBy], documents[i].fields[orderBy])
+			}
+			return less(documents[i].fields[orderBy], documents[j].fields[orderBy])
+		})
+	}
+
+	// Page the documents
+	if queryParams.Page > 0 && queryParams.PageSize > 0 {
+		start := (queryParams.Page - 1) * queryParams.PageSize
+		if start >= len(documents) {
+			return nil, nil
+		}
+		documents = documents[start:min(start+queryParams.PageSize, len(documents))]
+	}
+	return
+}
+
+// nextID will return the next ID of the table
+func (c *Client) nextID(table string) (id uint64, err error) {
+	err = c.db.Update(func(btx *bbolt.Tx) error {
+		bucket, bucketErr := btx.CreateBucketIfNotExists([]byte(table))
+		if bucketErr != nil {
+			return bucketErr
+		}
+		id, bucketErr = bucket.NextSequence()
+		return bucketErr
+	})
+	return
+}
+
+// tableName will return the table (bucket) name of the model or slice of models
+func (c *Client) tableName(model interface{}) (string, error) {
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && (modelType.Kind() == reflect.Ptr || modelType.Kind() == reflect.Slice) {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return "", ErrUnknownModel
+	}
+	namer, ok := reflect.New(modelType).Interface().(tableNamer)
+	if !ok {
+		return "", ErrUnknownModel
+	}
+	return c.GetTableName(namer.GetModelTableName()), nil
+}
+
+// put will write the document into its bucket
+func put(btx *bbolt.Tx, w *write) error {
+	bucket, err := btx.CreateBucketIfNotExists([]byte(w.table))
+	if err != nil {
+		return err
+	}
+
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, w.id)
+	old := bucket.Get(key)
+	if w.newRecord && old != nil {
+		return datastore.ErrDuplicateKey
+	}
+	if err = updateUniqueIndexes(btx, w.table, key, old, w.data, w.unique); err != nil {
+		return err
+	}
+
+	// Keep the sequence ahead of the IDs that were set by the caller
+	if w.id > bucket.Sequence() {
+		if err = bucket.SetSequence(w.id); err != nil {
+			return err
+		}
+	}
+	return bucket.Put(key, w.data)
+}
+
+// uniqueFields returns the document fields of the model (or its type) that have a gorm uniqueIndex
+func uniqueFields(modelType reflect.Type) (fields []string) {
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if field.Anonymous && strings.Contains(options, "inline") {
+			fields = append(fields, uniqueFields(field.Type)...)
+			continue
+		}
+		for _, option := range strings.Split(field.Tag.Get("gorm"), ";") {
+			if option != uniqueIndexTag && !strings.HasPrefix(option, uniqueIndexTag+":") {
+				continue
+			}
+			if len(name) == 0 {
+				name = strings.ToLower(field.Name) // The default name of the BSON field
+			}
+			fields = append(fields, name)
+			break
+		}
+	}
+	return fields
+}
+
+// uniqueIndexName returns the name of the bucket indexing the unique field of the table (value: document key)
+func uniqueIndexName(table, field string) []byte {
+	return []byte(table + uniqueIndexSeparator + field)
+}
+
+// uniqueIndex will return the index bucket of the unique field, the index is created from the documents
+// of the table if it does not exist (they were saved before the index existed)
+func uniqueIndex(btx *bbolt.Tx, table, field string) (*bbolt.Bucket, error) {
+	if index := btx.Bucket(uniqueIndexName(table, field)); index != nil {
+		return index, nil
+	}
+	index, err := btx.CreateBucket(uniqueIndexName(table, field))
+	if err != nil {
+		return nil, err
+	}
+	bucket := btx.Bucket([]byte(table))
+	if bucket == nil {
+		return index, nil
+	}
+	return index, bucket.ForEach(func(k, v []byte) error {
+		if value := uniqueValue(v, field); value != nil {
+			return index.Put(value, k)
+		}
+		return nil
+	})
+}
+
+// uniqueValue returns the index key of the field of the document (nil: the document does not have the field)
+func uniqueValue(data []byte, field string) []byte {
+	value, err := bson.Raw(data).LookupErr(field)
+	if err != nil {
+		return nil
+	}
+	return append([]byte{byte(value.Type)}, value.Value...)
+}
+
+// updateUniqueIndexes will index the unique fields of the document (replacing the values of the old document),
+// and return datastore.ErrDuplicateKey if another document has one of the values
+func updateUniqueIndexes(btx *bbolt.Tx, table string, key, old, data []byte, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	old = append([]byte(nil), old...) // Only valid until the transaction changes
+	for _, field := range fields {
+		index, err := uniqueIndex(btx, table, field)
+		if err != nil {
+			return err
+		}
+		value := uniqueValue(data, field)
+		if value != nil {
+			if id := index.Get(value); id != nil && !bytes.Equal(id, key) {
+				return fmt.Errorf("%w: %s has the %s of another document", datastore.ErrDuplicateKey, table, field)
+			}
+		}
+		if oldValue := uniqueValue(old, field); oldValue != nil && !bytes.Equal(oldValue, value) &&
+			bytes.Equal(index.Get(oldValue), key) {
+			if err = index.Delete(oldValue); err != nil {
+				return err
+			}
+		}
+		if value != nil {
+			if err = index.Put(value, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
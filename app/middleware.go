@@ -1,11 +1,19 @@
 package app
 
 import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
 	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/julienschmidt/httprouter"
 	apirouter "github.com/mrz1836/go-api-router"
 )
 
+// ErrAdminUnauthorized is returned when an admin request does not have the admin token
+var ErrAdminUnauthorized = errors.New("admin token is missing or invalid")
+
 // NewStack is used for registering routes
 func NewStack(conf *config.Config) (Action, *apirouter.InternalStack) {
 	return Action{Config: conf}, apirouter.NewStack()
@@ -19,3 +27,24 @@ func (a *Action) Request(router *apirouter.Router, h httprouter.Handle) httprout
 	}
 	return router.RequestNoLogging(h)
 }
+
+// AdminRequest will process an admin request in the router (see Request), the request must have the admin
+// token of the web server (Authorization: Bearer <token>)
+//
+// Only register the admin requests if the admin token is set (web_server.admin_token)
+func (a *Action) AdminRequest(router *apirouter.Router, h httprouter.Handle) httprouter.Handle {
+	return a.Request(router, func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		if !a.isAdmin(req) {
+			APIErrorResponse(w, req, http.StatusUnauthorized, ErrAdminUnauthorized)
+			return
+		}
+		h(w, req, ps)
+	})
+}
+
+// isAdmin returns true if the request has the admin token (an empty admin token is never valid)
+func (a *Action) isAdmin(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	adminToken := a.Config.WebServer.AdminToken
+	return ok && len(adminToken) > 0 && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/bitcoin-sv/alert-system/app/config"
@@ -53,3 +54,38 @@ func TestAction_Request(t *testing.T) {
 		a.Request(router, testHandle)
 	})
 }
+
+// TestAction_AdminRequest will test the method AdminRequest()
+func TestAction_AdminRequest(t *testing.T) {
+	t.Parallel()
+
+	// serve will make the request with the authorization header, and return true if the handler was called
+	serve := func(adminToken, authorization string) (called bool) {
+		dep := new(config.Config)
+		dep.WebServer.AdminToken = adminToken
+		a, _ := NewStack(dep)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if len(authorization) > 0 {
+			req.Header.Set("Authorization", authorization)
+		}
+		a.AdminRequest(apirouter.New(), func(http.ResponseWriter, *http.Request, httprouter.Params) {
+			called = true
+		})(httptest.NewRecorder(), req, nil)
+		return
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		require.True(t, serve("secret", "Bearer secret"))
+	})
+
+	t.Run("missing or invalid token", func(t *testing.T) {
+		require.False(t, serve("secret", ""))
+		require.False(t, serve("secret", "Bearer wrong"))
+		require.False(t, serve("secret", "secret"))
+	})
+
+	t.Run("no admin token", func(t *testing.T) {
+		require.False(t, serve("", "Bearer "))
+	})
+}
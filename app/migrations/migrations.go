@@ -0,0 +1,153 @@
+// Package migrations applies the versioned changes to the datastore
+//
+// The tables are created and extended by the datastore auto migration (from the models), the
+// versioned migrations make the changes it cannot (renames, backfills and drops). Every applied
+// version is recorded in the migrations table and a datastore with a version newer than the
+// binary knows is refused, so an older binary never runs against a schema it does not understand.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/mrz1836/go-datastore"
+	"gorm.io/gorm"
+)
+
+// Errors returned by the migrations
+var (
+	ErrPendingMigrations = errors.New("datastore has pending migrations, run the migrate up command")
+	ErrSchemaNewer       = errors.New("datastore schema is newer than this version of the alert system")
+)
+
+// Migration is a versioned change to the datastore
+type Migration struct {
+	Version     uint32                                               // Version (migrations are applied in version order)
+	Description string                                               // Short description for the status
+	Up          func(ctx context.Context, conf *config.Config) error // Applies the change
+}
+
+// all is every migration known by the binary (in version order, never remove or renumber one)
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "backfill the key set history from the saved set keys alerts",
+		Up: func(ctx context.Context, conf *config.Config) error {
+			return models.BackfillKeySetEpochs(ctx, model.WithAllDependencies(conf))
+		},
+	},
+}
+
+// Latest returns the latest version known by the binary
+func Latest() uint32 {
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// Status is the migration state of the datastore
+type Status struct {
+	Applied []*models.SchemaMigration // Applied migrations (in version order)
+	Current uint32                    // Latest applied version (0 if none)
+	Latest  uint32                    // Latest version known by the binary
+	Pending []Migration               // Migrations not applied yet (in version order)
+}
+
+// GetStatus will get the migration state of the datastore
+func GetStatus(ctx context.Context, conf *config.Config) (*Status, error) {
+	return getStatus(ctx, conf, all)
+}
+
+// Check will return an error if the datastore schema is newer than the binary or has pending migrations
+func Check(ctx context.Context, conf *config.Config) error {
+	status, err := GetStatus(ctx, conf)
+	if err != nil {
+		return err
+	} else if status.Current > status.Latest {
+		return fmt.Errorf("%w: datastore version %d, latest known version %d", ErrSchemaNewer, status.Current, status.Latest)
+	} else if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: datastore version %d, latest version %d", ErrPendingMigrations, status.Current, status.Latest)
+	}
+	return nil
+}
+
+// Up will apply the pending migrations (in version order) and return the applied migrations
+func Up(ctx context.Context, conf *config.Config) ([]Migration, error) {
+	return up(ctx, conf, all)
+}
+
+// getStatus will get the migration state of the datastore for the given migrations
+func getStatus(ctx context.Context, conf *config.Config, migrations []Migration) (*Status, error) {
+	if err := createMigrationsTable(ctx, conf.Services.Datastore); err != nil {
+		return nil, err
+	}
+
+	applied, err := models.GetSchemaMigrations(ctx, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Applied: applied}
+	if len(migrations) > 0 {
+		status.Latest = migrations[len(migrations)-1].Version
+	}
+	versions := make(map[uint32]bool, len(applied))
+	for _, migration := range applied {
+		versions[migration.Version] = true
+		if migration.Version > status.Current {
+			status.Current = migration.Version
+		}
+	}
+	for _, migration := range migrations {
+		if !versions[migration.Version] {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// up will apply the given migrations that are pending
+func up(ctx context.Context, conf *config.Config, migrations []Migration) ([]Migration, error) {
+	status, err := getStatus(ctx, conf, migrations)
+	if err != nil {
+		return nil, err
+	} else if status.Current > status.Latest {
+		return nil, fmt.Errorf("%w: datastore version %d, latest known version %d", ErrSchemaNewer, status.Current, status.Latest)
+	}
+
+	applied := make([]Migration, 0, len(status.Pending))
+	for _, migration := range status.Pending {
+		conf.Services.Log.Infof("applying migration %d: %s", migration.Version, migration.Description)
+		if err = migration.Up(ctx, conf); err != nil {
+			return applied, fmt.Errorf("migration %d failed: %w", migration.Version, err)
+		}
+
+		// Record the migration
+		record := models.NewSchemaMigration(model.WithAllDependencies(conf), model.New())
+		record.Version = migration.Version
+		record.Description = migration.Description
+		if err = record.Save(ctx); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// createMigrationsTable will create the migrations table if it does not exist (the datastore
+// auto migration is not required to run the migrations)
+func createMigrationsTable(ctx context.Context, ds datastore.ClientInterface) error {
+	if ds == nil {
+		return model.ErrMissingDatastore
+	} else if !datastore.IsSQLEngine(ds.Engine()) { // Collections are created on the first write
+		return nil
+	}
+	return ds.Raw("").Session(&gorm.Session{NewDB: true, Context: ctx}).AutoMigrate(
+		models.NewSchemaMigration(),
+	)
+}
@@ -0,0 +1,108 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLatest will test the method Latest()
+func TestLatest(t *testing.T) {
+	t.Parallel()
+
+	// The migrations must be in version order
+	for i := 1; i < len(all); i++ {
+		assert.Greater(t, all[i].Version, all[i-1].Version)
+	}
+	assert.Equal(t, all[len(all)-1].Version, Latest())
+}
+
+// TestUp will test the methods Up(), GetStatus() and Check()
+func (ts *TestSuite) TestUp() {
+	ctx := context.Background()
+
+	ts.Run("pending migrations", func() {
+		status, err := GetStatus(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Equal(uint32(0), status.Current)
+		ts.Equal(Latest(), status.Latest)
+		ts.Len(status.Pending, len(all))
+		ts.ErrorIs(Check(ctx, ts.Dependencies), ErrPendingMigrations)
+	})
+
+	ts.Run("apply all", func() {
+		applied, err := Up(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Len(applied, len(all))
+		ts.Require().NoError(Check(ctx, ts.Dependencies))
+
+		// The key set history was backfilled
+		var epoch *models.KeySetEpoch
+		epoch, err = models.GetKeySetEpochByStartSequence(ctx, 0, model.WithAllDependencies(ts.Dependencies))
+		ts.Require().NoError(err)
+		ts.NotNil(epoch)
+
+		// Nothing left to apply
+		applied, err = Up(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Empty(applied)
+
+		var status *Status
+		status, err = GetStatus(ctx, ts.Dependencies)
+		ts.Require().NoError(err)
+		ts.Equal(Latest(), status.Current)
+		ts.Len(status.Applied, len(all))
+		ts.Empty(status.Pending)
+	})
+
+	ts.Run("schema is newer than the binary", func() {
+		record := models.NewSchemaMigration(model.WithAllDependencies(ts.Dependencies), model.New())
+		record.Version = Latest() + 1
+		record.Description = "from a newer version"
+		ts.Require().NoError(record.Save(ctx))
+
+		ts.ErrorIs(Check(ctx, ts.Dependencies), ErrSchemaNewer)
+		_, err := Up(ctx, ts.Dependencies)
+		ts.ErrorIs(err, ErrSchemaNewer)
+	})
+}
+
+// TestUp_Failure will test a failing migration
+func (ts *TestSuite) TestUp_Failure() {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	var ran []uint32
+	migrations := []Migration{
+		{Version: 1, Description: "first", Up: func(_ context.Context, _ *config.Config) error {
+			ran = append(ran, 1)
+			return nil
+		}},
+		{Version: 2, Description: "second", Up: func(_ context.Context, _ *config.Config) error {
+			ran = append(ran, 2)
+			return errFailed
+		}},
+		{Version: 3, Description: "third", Up: func(_ context.Context, _ *config.Config) error {
+			ran = append(ran, 3)
+			return nil
+		}},
+	}
+
+	// The migrations stop at the failure
+	applied, err := up(ctx, ts.Dependencies, migrations)
+	ts.Require().ErrorIs(err, errFailed)
+	ts.Len(applied, 1)
+	ts.Equal([]uint32{1, 2}, ran)
+
+	// The failed migration is retried
+	var status *Status
+	status, err = getStatus(ctx, ts.Dependencies, migrations)
+	ts.Require().NoError(err)
+	ts.Equal(uint32(1), status.Current)
+	ts.Len(status.Pending, 2)
+	ts.Equal(uint32(2), status.Pending[0].Version)
+}
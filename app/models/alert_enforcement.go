@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// Statuses of an enforcement window at a block height
+const (
+	EnforcementActive   = "active"   // The block height is in the window
+	EnforcementExpired  = "expired"  // The window ended before the block height
+	EnforcementUpcoming = "upcoming" // The window starts after the block height
+)
+
+// EnforcementWindow is the range of block heights where the node enforces an alert (frozen funds are
+// frozen, a confiscation transaction is valid): from Start, up to (not including) Stop
+type EnforcementWindow struct {
+	AlertType      string `json:"alert_type"`      // Freeze, Unfreeze or Confiscate
+	SequenceNumber uint32 `json:"sequence_number"` // The alert
+	Start          int64  `json:"start"`           // The first block height
+	Status         string `json:"status"`          // active, expired or upcoming (at the height of the schedule)
+	Stop           int64  `json:"stop"`            // The first block height after the window (0: no end)
+	Target         string `json:"target"`          // fund:<txid>:<vout> or confiscation:<txid> (see AlertMessageVerifyInterface)
+}
+
+// StatusAt returns the status of the window at the block height
+func (w *EnforcementWindow) StatusAt(height int64) string {
+	switch {
+	case height < w.Start:
+		return EnforcementUpcoming
+	case w.Stop > 0 && height >= w.Stop:
+		return EnforcementExpired
+	}
+	return EnforcementActive
+}
+
+// AlertMessageEnforcementInterface is implemented by alert messages that are enforced at block heights
+type AlertMessageEnforcementInterface interface {
+	AlertMessageInterface
+
+	// EnforcementWindows returns the windows of the alert (without the alert and the status)
+	EnforcementWindows() []*EnforcementWindow
+}
+
+// ExpiredEnforcementWindows returns the windows of the alert that already ended at the block height
+func ExpiredEnforcementWindows(alert *AlertMessage, am AlertMessageEnforcementInterface, height int64) []*EnforcementWindow {
+	expired := make([]*EnforcementWindow, 0)
+	for _, window := range am.EnforcementWindows() {
+		if window.StatusAt(height) == EnforcementExpired {
+			window.AlertType = alert.GetAlertType().Name()
+			window.SequenceNumber = alert.SequenceNumber
+			window.Status = EnforcementExpired
+			expired = append(expired, window)
+		}
+	}
+	return expired
+}
+
+// warnExpiredAlert will log a warning for every enforcement window of the alert that already ended (the
+// alert arrived too late), if the block height of the node is known
+func warnExpiredAlert(alert *AlertMessage, am AlertMessageEnforcementInterface) {
+	height, updatedAt := alert.Config().Services.Chain.Height()
+	if updatedAt.IsZero() {
+		return
+	}
+	for _, window := range ExpiredEnforcementWindows(alert, am, height) {
+		alert.Config().Services.Log.Warnf(
+			"alert %d arrived after its enforcement of %s ended at height %d (block height %d)",
+			alert.SequenceNumber, window.Target, window.Stop, height,
+		)
+	}
+}
+
+// EnforcementSchedule is the enforcement windows of the alerts at a block height
+type EnforcementSchedule struct {
+	Active   []*EnforcementWindow `json:"active"`   // The windows the block height is in
+	Expired  []*EnforcementWindow `json:"expired"`  // The windows that ended (the most recent first)
+	Height   int64                `json:"height"`   // The block height of the schedule
+	Upcoming []*EnforcementWindow `json:"upcoming"` // The windows that did not start (the next first)
+}
+
+// GetEnforcementWindows returns the enforcement windows of the alerts (in sequence order), without the
+// alerts that were replaced by a later alert (an unfreeze replaces the freeze of the same funds)
+func GetEnforcementWindows(ctx context.Context, height int64, opts ...model.Options) ([]*EnforcementWindow, error) {
+	nodeAlerts, err := getNodeAlerts(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]*EnforcementWindow, 0)
+	for _, na := range nodeAlerts {
+		am, ok := na.message.(AlertMessageEnforcementInterface)
+		if !ok || na.superseded {
+			continue
+		}
+		for _, window := range am.EnforcementWindows() {
+			window.AlertType = na.alert.GetAlertType().Name()
+			window.SequenceNumber = na.alert.SequenceNumber
+			window.Status = window.StatusAt(height)
+			windows = append(windows, window)
+		}
+	}
+	return windows, nil
+}
+
+// GetEnforcementSchedule returns the upcoming, active and expired enforcement windows at the block height
+func GetEnforcementSchedule(ctx context.Context, height int64, opts ...model.Options) (*EnforcementSchedule, error) {
+	windows, err := GetEnforcementWindows(ctx, height, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &EnforcementSchedule{
+		Active:   make([]*EnforcementWindow, 0),
+		Expired:  make([]*EnforcementWindow, 0),
+		Height:   height,
+		Upcoming: make([]*EnforcementWindow, 0),
+	}
+	for _, window := range windows {
+		switch window.Status {
+		case EnforcementActive:
+			schedule.Active = append(schedule.Active, window)
+		case EnforcementExpired:
+			schedule.Expired = append(schedule.Expired, window)
+		default:
+			schedule.Upcoming = append(schedule.Upcoming, window)
+		}
+	}
+	sort.SliceStable(schedule.Expired, func(i, j int) bool {
+		return schedule.Expired[i].Stop > schedule.Expired[j].Stop
+	})
+	sort.SliceStable(schedule.Upcoming, func(i, j int) bool {
+		return schedule.Upcoming[i].Start < schedule.Upcoming[j].Start
+	})
+	return schedule, nil
+}
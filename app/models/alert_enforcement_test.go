@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnforcementWindow_StatusAt will test the method StatusAt()
+func TestEnforcementWindow_StatusAt(t *testing.T) {
+	t.Parallel()
+
+	window := &EnforcementWindow{Start: 100, Stop: 200}
+	assert.Equal(t, EnforcementUpcoming, window.StatusAt(99))
+	assert.Equal(t, EnforcementActive, window.StatusAt(100))
+	assert.Equal(t, EnforcementActive, window.StatusAt(199))
+	assert.Equal(t, EnforcementExpired, window.StatusAt(200))
+
+	// No end
+	window = &EnforcementWindow{Start: 100}
+	assert.Equal(t, EnforcementActive, window.StatusAt(1_000_000))
+}
+
+// TestAlertMessage_EnforcementWindows will test the enforcement windows of the alert messages
+func TestAlertMessage_EnforcementWindows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("freeze", func(t *testing.T) {
+		freeze := &AlertMessageFreezeUtxo{Funds: []models.Fund{{
+			TxOut:           models.TxOut{TxId: "abcd", Vout: 1},
+			EnforceAtHeight: []models.Enforce{{Start: 100, Stop: 200}, {Start: 300, Stop: -1}},
+		}}}
+		windows := freeze.EnforcementWindows()
+		require.Len(t, windows, 2)
+		assert.Equal(t, EnforcementWindow{Start: 100, Stop: 200, Target: "fund:abcd:1"}, *windows[0])
+		assert.Equal(t, EnforcementWindow{Start: 300, Target: "fund:abcd:1"}, *windows[1])
+	})
+
+	t.Run("confiscation", func(t *testing.T) {
+		confiscation := &AlertMessageConfiscateTransaction{Transactions: []models.ConfiscationTransactionDetails{{
+			ConfiscationTransaction: models.ConfiscationTransaction{EnforceAtHeight: 500, Hex: "00"},
+		}}}
+		windows := confiscation.EnforcementWindows()
+		require.Len(t, windows, 1)
+		assert.Equal(t, int64(500), windows[0].Start)
+		assert.Equal(t, int64(0), windows[0].Stop)
+		assert.Equal(t, confiscation.Targets()[0], windows[0].Target)
+	})
+}
@@ -7,14 +7,16 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/bitcoin-sv/alert-system/app/models/model"
 	"github.com/bitcoin-sv/alert-system/utils"
 	"github.com/bitcoinschema/go-bitcoin"
-	"github.com/bitcoinsv/bsvd/bsvec"
 	"github.com/bitcoinsv/bsvutil"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // AlertMessage is an object representing an alert message
@@ -28,6 +30,7 @@ type AlertMessage struct {
 	SequenceNumber uint32 `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the alert sequence number"`
 	Raw            string `json:"raw" toml:"raw" yaml:"raw" bson:"raw" gorm:"<-;type:text;comment:This is the raw alert message"`
 	Processed      bool   `json:"processed" toml:"processed" yaml:"processed" bson:"processed" gorm:"<-;type:boolean;comment:This determine if the alert was processed"`
+	Verification   string `json:"verification" toml:"verification" yaml:"verification" bson:"verification" gorm:"<-;type:varchar(16);comment:This is the result of checking the effect on the node"`
 
 	// Private fields (never to be exported)
 	alertType  AlertType
@@ -63,6 +66,19 @@ func (m *AlertMessage) GetTableName() string {
 	return model.TableAlertMessages
 }
 
+// GetModelTableName will get the collection name of the model (MongoDB)
+func (m *AlertMessage) GetModelTableName() string {
+	return model.TableAlertMessages
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *AlertMessage) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}},
+		{Keys: bson.D{{Key: "sequence_number", Value: 1}}},
+	}
+}
+
 // GetID will get the model ID
 func (m *AlertMessage) GetID() uint64 {
 	return m.ID
@@ -141,12 +157,42 @@ func (m *AlertMessage) SetSignatures(sigs [][]byte) {
 	m.signatures = sigs
 }
 
-// AreSignaturesValid checks if the signatures are valid
+// AreSignaturesValid checks if the signatures are valid against the latest key set (a new alert, the
+// next sequence after the latest alert)
 func (m *AlertMessage) AreSignaturesValid(ctx context.Context) (bool, error) {
-	keys, err := GetActivePublicKey(ctx, nil, model.WithAllDependencies(m.Config()))
+	return m.areSignaturesValidAt(ctx, math.MaxUint32)
+}
+
+// AreHistoricalSignaturesValid checks if the signatures are valid against the key set active at the alert
+// sequence (an alert that is already saved, do not use it to accept an alert)
+func (m *AlertMessage) AreHistoricalSignaturesValid(ctx context.Context) (bool, error) {
+	return m.areSignaturesValidAt(ctx, m.SequenceNumber)
+}
+
+// areSignaturesValidAt checks if the signatures are valid against the key set active at the sequence
+func (m *AlertMessage) areSignaturesValidAt(ctx context.Context, sequenceNumber uint32) (bool, error) {
+	epoch, err := GetKeySetEpochForSequence(ctx, sequenceNumber, model.WithAllDependencies(m.Config()))
 	if err != nil {
 		return false, err
-	} else if len(keys) == 0 {
+	} else if epoch != nil {
+		return m.AreSignaturesValidWithKeys(epoch.PublicKeys())
+	}
+
+	// No key set history (not backfilled yet), use the active keys
+	var keys []*PublicKey
+	if keys, err = GetActivePublicKey(ctx, nil, model.WithAllDependencies(m.Config())); err != nil {
+		return false, err
+	}
+	pubKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pubKeys = append(pubKeys, key.Key)
+	}
+	return m.AreSignaturesValidWithKeys(pubKeys)
+}
+
+// AreSignaturesValidWithKeys checks if every signature is from one of the given public keys (hex)
+func (m *AlertMessage) AreSignaturesValidWithKeys(keys []string) (bool, error) {
+	if len(keys) == 0 {
 		return false, fmt.Errorf("no active public keys found")
 	}
 
@@ -159,8 +205,8 @@ func (m *AlertMessage) AreSignaturesValid(ctx context.Context) (bool, error) {
 		for _, key := range keys {
 
 			// Get the public key
-			var pub *bsvec.PublicKey
-			if pub, err = bitcoin.PubKeyFromString(key.Key); err != nil {
+			pub, err := bitcoin.PubKeyFromString(key)
+			if err != nil {
 				return false, err
 			}
 
@@ -174,7 +220,9 @@ func (m *AlertMessage) AreSignaturesValid(ctx context.Context) (bool, error) {
 
 			// Verify the message
 			if err = bitcoin.VerifyMessage(addr.String(), b64Sig, hex.EncodeToString(m.data)); err != nil {
-				m.Config().Services.Log.Debugf("error verifying signature %x: %v", sig, err)
+				if m.Config() != nil {
+					m.Config().Services.Log.Debugf("error verifying signature %x: %v", sig, err)
+				}
 				continue
 			}
 			valid = true
@@ -437,3 +485,34 @@ func GetAllUnprocessedAlerts(ctx context.Context, metadata *model.Metadata, opts
 	// Return the first item (only item)
 	return modelItems, nil
 }
+
+// GetAlertsAfterSequence will get all alerts with a sequence number greater than the given sequence
+func GetAlertsAfterSequence(ctx context.Context, sequenceNumber uint32, metadata *model.Metadata,
+	opts ...model.Options) ([]*AlertMessage, error) {
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		utils.FieldSequenceNumber: map[string]interface{}{
+			utils.GreaterThanCondition: sequenceNumber,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldSequenceNumber,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*AlertMessage, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameAlertMessage, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
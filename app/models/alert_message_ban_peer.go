@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-sdk/util"
 )
 
@@ -59,7 +61,22 @@ func (a *AlertMessageBanPeer) Read(alert []byte) error {
 
 // Do execute the alert
 func (a *AlertMessageBanPeer) Do(ctx context.Context) error {
-	return a.Config().Services.Node.BanPeer(ctx, string(a.Peer))
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will ban the peer on the node
+func (a *AlertMessageBanPeer) DoOnNode(ctx context.Context, node config.NodeInterface) error {
+	return node.BanPeer(ctx, string(a.Peer))
+}
+
+// Targets returns the peer that is banned
+func (a *AlertMessageBanPeer) Targets() []string {
+	return []string{"peer:" + string(a.Peer)}
+}
+
+// Verify will check that the peer is banned on the node
+func (a *AlertMessageBanPeer) Verify(ctx context.Context) (bool, error) {
+	return a.Config().Services.Node.IsPeerBanned(ctx, string(a.Peer))
 }
 
 // ToJSON is the alert in JSON format
@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/bsv-blockchain/go-sdk/util"
 
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-bn/models"
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-sdk/util"
 )
 
 // AlertMessageConfiscateTransaction is a confiscate utxo alert
@@ -67,8 +69,13 @@ func (a *AlertMessageConfiscateTransaction) Read(raw []byte) error {
 
 // Do execute the alert
 func (a *AlertMessageConfiscateTransaction) Do(ctx context.Context) error {
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will whitelist the confiscation transactions on the node
+func (a *AlertMessageConfiscateTransaction) DoOnNode(ctx context.Context, node config.NodeInterface) error {
 	a.Config().Services.Log.Infof("ConfiscateTransaction alert; enforceAt [%d]; hex [%s]", a.Transactions[0].ConfiscationTransaction.EnforceAtHeight, hex.EncodeToString(a.GetRawMessage()))
-	res, err := a.Config().Services.Node.AddToConfiscationTransactionWhitelist(ctx, a.Transactions)
+	res, err := node.AddToConfiscationTransactionWhitelist(ctx, a.Transactions)
 	if err != nil {
 		return err
 	}
@@ -79,6 +86,43 @@ func (a *AlertMessageConfiscateTransaction) Do(ctx context.Context) error {
 	return nil
 }
 
+// Targets returns the confiscation transactions that are whitelisted
+func (a *AlertMessageConfiscateTransaction) Targets() []string {
+	targets := make([]string, 0, len(a.Transactions))
+	for _, tx := range a.Transactions {
+		targets = append(targets, "confiscation:"+confiscationTxID(tx))
+	}
+	return targets
+}
+
+// Verify will check that the confiscation transactions are whitelisted on the node
+func (a *AlertMessageConfiscateTransaction) Verify(ctx context.Context) (bool, error) {
+	for _, tx := range a.Transactions {
+		if whitelisted, err := a.Config().Services.Node.IsConfiscationWhitelisted(ctx, confiscationTxID(tx)); err != nil || !whitelisted {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// EnforcementWindows returns the heights from which the confiscation transactions are valid
+func (a *AlertMessageConfiscateTransaction) EnforcementWindows() []*EnforcementWindow {
+	windows := make([]*EnforcementWindow, 0, len(a.Transactions))
+	for _, tx := range a.Transactions {
+		windows = append(windows, &EnforcementWindow{
+			Start:  tx.ConfiscationTransaction.EnforceAtHeight,
+			Target: "confiscation:" + confiscationTxID(tx),
+		})
+	}
+	return windows
+}
+
+// confiscationTxID returns the transaction ID of the confiscation transaction
+func confiscationTxID(tx models.ConfiscationTransactionDetails) string {
+	raw, _ := hex.DecodeString(tx.ConfiscationTransaction.Hex)
+	return chainhash.DoubleHashH(raw).String()
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageConfiscateTransaction) ToJSON(_ context.Context) []byte {
 	m := a.ProcessAlertMessage()
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-bn/models"
 )
 
@@ -84,13 +85,68 @@ func (a *AlertMessageFreezeUtxo) Read(raw []byte) error {
 
 // Do perform the message
 func (a *AlertMessageFreezeUtxo) Do(ctx context.Context) error {
-	_, err := a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will freeze the funds on the node
+func (a *AlertMessageFreezeUtxo) DoOnNode(ctx context.Context, node config.NodeInterface) error {
+	_, err := node.AddToConsensusBlacklist(ctx, a.Funds)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Targets returns the funds that are frozen
+func (a *AlertMessageFreezeUtxo) Targets() []string {
+	return fundTargets(a.Funds)
+}
+
+// Verify will check that the funds are in the consensus blacklist of the node (with the enforcement heights)
+func (a *AlertMessageFreezeUtxo) Verify(ctx context.Context) (bool, error) {
+	return areFundsBlacklisted(ctx, a.Config().Services.Node, a.Funds)
+}
+
+// fundTargets returns the targets of the funds (fund:<txid>:<vout>)
+func fundTargets(funds []models.Fund) []string {
+	targets := make([]string, 0, len(funds))
+	for _, fund := range funds {
+		targets = append(targets, fmt.Sprintf("fund:%s:%d", fund.TxOut.TxId, fund.TxOut.Vout))
+	}
+	return targets
+}
+
+// EnforcementWindows returns the heights where the funds are frozen
+func (a *AlertMessageFreezeUtxo) EnforcementWindows() []*EnforcementWindow {
+	return fundWindows(a.Funds)
+}
+
+// fundWindows returns the enforcement windows of the funds (a stop height that does not fit is no end)
+func fundWindows(funds []models.Fund) []*EnforcementWindow {
+	windows := make([]*EnforcementWindow, 0, len(funds))
+	for _, fund := range funds {
+		target := fmt.Sprintf("fund:%s:%d", fund.TxOut.TxId, fund.TxOut.Vout)
+		for _, enforce := range fund.EnforceAtHeight {
+			window := &EnforcementWindow{Start: int64(enforce.Start), Target: target}
+			if enforce.Stop > 0 {
+				window.Stop = int64(enforce.Stop)
+			}
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// areFundsBlacklisted returns true if every fund is in the consensus blacklist of the node
+func areFundsBlacklisted(ctx context.Context, node config.NodeInterface, funds []models.Fund) (bool, error) {
+	for _, fund := range funds {
+		if blacklisted, err := node.IsFundBlacklisted(ctx, fund); err != nil || !blacklisted {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageFreezeUtxo) ToJSON(_ context.Context) []byte {
 	m := a.ProcessAlertMessage()
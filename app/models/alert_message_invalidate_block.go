@@ -4,9 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/bsv-blockchain/go-sdk/util"
 
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/bsv-blockchain/go-sdk/util"
 )
 
 // AlertMessageInvalidateBlock is an invalidate block alert
@@ -53,8 +54,23 @@ func (a *AlertMessageInvalidateBlock) Read(alert []byte) error {
 
 // Do execute the alert
 func (a *AlertMessageInvalidateBlock) Do(ctx context.Context) error {
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will invalidate the block on the node
+func (a *AlertMessageInvalidateBlock) DoOnNode(ctx context.Context, node config.NodeInterface) error {
 	a.Config().Services.Log.Infof("InvalidateBlock alert; hash [%s]; reason [%s]", a.BlockHash, a.Reason)
-	return a.Config().Services.Node.InvalidateBlock(ctx, a.BlockHash.String())
+	return node.InvalidateBlock(ctx, a.BlockHash.String())
+}
+
+// Targets returns the block that is invalidated
+func (a *AlertMessageInvalidateBlock) Targets() []string {
+	return []string{"block:" + a.BlockHash.String()}
+}
+
+// Verify will check that the block is invalid on the node (on the branch of an invalid chain tip)
+func (a *AlertMessageInvalidateBlock) Verify(ctx context.Context) (bool, error) {
+	return a.Config().Services.Node.IsBlockInvalid(ctx, a.BlockHash.String())
 }
 
 // ToJSON is the alert in JSON format
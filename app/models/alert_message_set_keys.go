@@ -47,27 +47,83 @@ func (a *AlertMessageSetKeys) Read(alert []byte) error {
 
 // Do execute the alert
 func (a *AlertMessageSetKeys) Do(ctx context.Context) error {
-	err := ClearActivePublicKeys(ctx, a.Config().Services.Datastore)
+	changes, err := a.Changes(ctx)
 	if err != nil {
 		return err
 	}
-	for _, key := range a.Keys {
-		pk := NewPublicKey(model.WithAllDependencies(a.Config()))
-		conditions := map[string]interface{}{
-			"key": hex.EncodeToString(key[:]),
+	return SaveWithTx(ctx, a.Config().Services.Datastore, changes...)
+}
+
+// Changes will get the public keys and key set changed by the alert, without saving them
+//
+// The new keys are activated and the rest of the active keys are deactivated, the key set
+// is recorded for validating historical alerts.
+func (a *AlertMessageSetKeys) Changes(ctx context.Context) ([]TxModel, error) {
+	opts := model.WithAllDependencies(a.Config())
+
+	// Get the active keys
+	active, err := GetActivePublicKey(ctx, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	activeKeys := make(map[string]*PublicKey, len(active))
+	for _, pk := range active {
+		pk.SetOptions(opts)
+		activeKeys[pk.Key] = pk
+	}
+
+	// Activate the new keys
+	keys := a.PublicKeys()
+	changes := make([]TxModel, 0, len(active)+len(keys)+1)
+	activated := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if activated[key] {
+			continue
 		}
-		err := model.Get(ctx, pk, conditions, 5*time.Second, false)
-		if !errors.Is(err, datastore.ErrNoResults) && err != nil {
-			return err
+		activated[key] = true
+		pk, ok := activeKeys[key]
+		if ok {
+			delete(activeKeys, key)
+		} else {
+			pk = NewPublicKey(opts)
+			conditions := map[string]interface{}{
+				"key": key,
+			}
+			if err = model.Get(ctx, pk, conditions, 5*time.Second, false); errors.Is(err, datastore.ErrNoResults) {
+				pk = NewPublicKey(opts, model.New())
+			} else if err != nil {
+				return nil, err
+			}
 		}
-		pk.Key = hex.EncodeToString(key[:])
+		pk.Key = key
 		pk.Active = true
 		pk.LastUpdateHash = a.Hash
-		if err = pk.Save(ctx); err != nil {
-			return err
+		changes = append(changes, pk)
+	}
+
+	// Deactivate the old keys (in the order they were loaded)
+	for _, pk := range active {
+		if _, ok := activeKeys[pk.Key]; ok {
+			pk.Active = false
+			changes = append(changes, pk)
 		}
 	}
-	return nil
+
+	// Record the key set for validating historical alerts
+	var epoch *KeySetEpoch
+	if epoch, err = keySetEpochChange(ctx, a.SequenceNumber, a.Hash, keys, opts); err != nil {
+		return nil, err
+	}
+	return append(changes, epoch), nil
+}
+
+// PublicKeys returns the new keys (hex)
+func (a *AlertMessageSetKeys) PublicKeys() []string {
+	keys := make([]string, 0, len(a.Keys))
+	for _, key := range a.Keys {
+		keys = append(keys, hex.EncodeToString(key[:]))
+	}
+	return keys
 }
 
 // ToJSON is the alert in JSON format
@@ -3,9 +3,12 @@ package models
 import (
 	"context"
 	"encoding/hex"
+	"path/filepath"
 	"testing"
 
+	"github.com/bitcoin-sv/alert-system/app/kvstore"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -108,6 +111,72 @@ func (ts *TestSuite) TestAlertMessage_GetLatestAlert() {
 	ts.Require().Equal(uint32(2), message.SequenceNumber)
 }
 
+// TestAlertMessage_GetAlertsAfterSequence will test getting the alerts after a sequence number
+func (ts *TestSuite) TestAlertMessage_GetAlertsAfterSequence() {
+
+	// Create three alert messages
+	for i := uint32(1); i <= 3; i++ {
+		message := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
+		message.Hash = testAlertHash + hex.EncodeToString([]byte{byte(i)})
+		message.Raw = testAlertRaw
+		message.SequenceNumber = i
+		ts.Require().NoError(message.Save(context.Background()))
+	}
+
+	// Get the alerts after the first one
+	messages, err := GetAlertsAfterSequence(context.Background(), 1, nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(messages, 2)
+	ts.Require().Equal(uint32(2), messages[0].SequenceNumber)
+	ts.Require().Equal(uint32(3), messages[1].SequenceNumber)
+
+	// Nothing after the last one
+	messages, err = GetAlertsAfterSequence(context.Background(), 3, nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Empty(messages)
+}
+
+// TestAlertMessage_KVStore will test the alert queries with the embedded key-value datastore
+func (ts *TestSuite) TestAlertMessage_KVStore() {
+	ctx := context.Background()
+
+	// Use the embedded datastore (the test datastore is closed)
+	client, err := kvstore.NewClient(&kvstore.Config{
+		DatabasePath: filepath.Join(ts.T().TempDir(), "alert_system_datastore.kv"),
+		TablePrefix:  ts.Dependencies.Datastore.TablePrefix,
+	})
+	ts.Require().NoError(err)
+	ts.Require().NoError(ts.Dependencies.Services.Datastore.Close(ctx))
+	ts.Dependencies.Services.Datastore = client
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+
+	// Create the genesis alert and rotate the keys
+	ts.Require().NoError(CreateGenesisAlert(ctx, opts...))
+	alert := newTestSetKeysAlert(ts.T(), opts, 1)
+	ts.Require().NoError(ApplyAlert(ctx, alert, alert.ProcessAlertMessage()))
+	ts.Require().True(alert.Processed)
+
+	message, err := GetAlertMessageBySequenceNumber(ctx, 1, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(message)
+	ts.Equal(alert.Hash, message.Hash)
+
+	message, err = GetLatestAlert(ctx, nil, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(message)
+	ts.Equal(uint32(1), message.SequenceNumber)
+
+	keys, err := GetActivePublicKey(ctx, nil, opts...)
+	ts.Require().NoError(err)
+	ts.Require().Len(keys, 5)
+	ts.Equal(utils.MainKey1, keys[0].Key)
+
+	epoch, err := GetKeySetEpochForSequence(ctx, 2, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(epoch)
+	ts.Equal(alert.Hash, epoch.Hash)
+}
+
 // TestAlertMessage_SerializeData will test serializing the data
 func (ts *TestSuite) TestAlertMessage_SerializeData() {
 	message := NewAlertMessage(model.WithAllDependencies(ts.Dependencies), model.New())
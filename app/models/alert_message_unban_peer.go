@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-sdk/util"
 )
 
@@ -62,7 +64,23 @@ func (a *AlertMessageUnbanPeer) Read(alert []byte) error {
 
 // Do execute the alert
 func (a *AlertMessageUnbanPeer) Do(ctx context.Context) error {
-	return a.Config().Services.Node.UnbanPeer(ctx, string(a.Peer))
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will unban the peer on the node
+func (a *AlertMessageUnbanPeer) DoOnNode(ctx context.Context, node config.NodeInterface) error {
+	return node.UnbanPeer(ctx, string(a.Peer))
+}
+
+// Targets returns the peer that is unbanned
+func (a *AlertMessageUnbanPeer) Targets() []string {
+	return []string{"peer:" + string(a.Peer)}
+}
+
+// Verify will check that the peer is not banned on the node
+func (a *AlertMessageUnbanPeer) Verify(ctx context.Context) (bool, error) {
+	banned, err := a.Config().Services.Node.IsPeerBanned(ctx, string(a.Peer))
+	return !banned && err == nil, err
 }
 
 // ToJSON is the alert in JSON format
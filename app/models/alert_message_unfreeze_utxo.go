@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bsv-blockchain/go-bn/models"
 )
 
@@ -62,13 +63,33 @@ func (a *AlertMessageUnfreezeUtxo) Read(raw []byte) error {
 
 // Do execute the message
 func (a *AlertMessageUnfreezeUtxo) Do(ctx context.Context) error {
-	_, err := a.Config().Services.Node.AddToConsensusBlacklist(ctx, a.Funds)
+	return a.DoOnNode(ctx, a.Config().Services.Node)
+}
+
+// DoOnNode will unfreeze the funds on the node
+func (a *AlertMessageUnfreezeUtxo) DoOnNode(ctx context.Context, node config.NodeInterface) error {
+	_, err := node.AddToConsensusBlacklist(ctx, a.Funds)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// Targets returns the funds that are unfrozen
+func (a *AlertMessageUnfreezeUtxo) Targets() []string {
+	return fundTargets(a.Funds)
+}
+
+// Verify will check that the funds are in the consensus blacklist of the node with the new enforcement heights
+func (a *AlertMessageUnfreezeUtxo) Verify(ctx context.Context) (bool, error) {
+	return areFundsBlacklisted(ctx, a.Config().Services.Node, a.Funds)
+}
+
+// EnforcementWindows returns the heights where the funds stay frozen
+func (a *AlertMessageUnfreezeUtxo) EnforcementWindows() []*EnforcementWindow {
+	return fundWindows(a.Funds)
+}
+
 // ToJSON is the alert in JSON format
 func (a *AlertMessageUnfreezeUtxo) ToJSON(_ context.Context) []byte {
 	m := a.ProcessAlertMessage()
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// AlertMessageNodeInterface is implemented by alert messages whose action can be performed on a given node
+type AlertMessageNodeInterface interface {
+	AlertMessageVerifyInterface
+
+	// DoOnNode will perform the action on the node (Do performs it on every node)
+	DoOnNode(ctx context.Context, node config.NodeInterface) error
+}
+
+// ReapplyAlerts will perform the action of every processed alert that is still relevant (no later alert
+// changed the same targets) on the node again, in sequence order, and return the number of alerts applied
+//
+// This is used when the node was restarted or reset (see config.NodePool.CheckRestarts): the alerts stay
+// processed, but their effect can be gone (e.g. a new data directory). It stops at the first action that
+// fails, so the node can be reconciled again later. The actions go through the circuit breaker of the node in
+// the pool (a failed action counts as a failure of the node)
+func ReapplyAlerts(ctx context.Context, pool *config.NodePool, node config.NodeInterface, opts ...model.Options) (int, error) {
+	nodeAlerts, err := getNodeAlerts(ctx, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, na := range nodeAlerts {
+		if na.superseded || !na.alert.Processed {
+			continue
+		}
+		am, ok := na.message.(AlertMessageNodeInterface)
+		if !ok {
+			continue
+		}
+		if err = pool.On(node, func(n config.NodeInterface) error {
+			return am.DoOnNode(ctx, n)
+		}); err != nil {
+			return applied, fmt.Errorf("failed to apply alert %d again on node %s: %w", na.alert.SequenceNumber, node.GetRPCHost(), err)
+		}
+		applied++
+	}
+	return applied, nil
+}
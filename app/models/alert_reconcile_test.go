@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/config/mocks"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// errTestReapply is returned by a test node that fails
+var errTestReapply = errors.New("connection refused")
+
+// newTestPool will create a node pool of the node (opening the circuit after a failure)
+func newTestPool(node config.NodeInterface) *config.NodePool {
+	return config.NewNodePool(config.NodeHealthConfig{FailureThreshold: 1, CircuitOpenTimeout: time.Minute}, nil, node)
+}
+
+// TestReapplyAlerts will test the method ReapplyAlerts()
+func (ts *TestSuite) TestReapplyAlerts() {
+	ctx := context.Background()
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+
+	for i, peer := range []string{"1.2.3.4", "5.6.7.8", "1.2.3.4"} {
+		alertType := AlertTypeBanPeer
+		if i == 2 {
+			alertType = AlertTypeUnbanPeer
+		}
+		alert := newTestPeerAlert(opts, alertType, uint32(i+1), peer)
+		am := alert.ProcessAlertMessage()
+		ts.Require().NoError(am.Read(alert.GetRawMessage()))
+		ts.Require().NoError(ApplyAlert(ctx, alert, am))
+	}
+
+	ts.Run("the still relevant alerts are applied in sequence order", func() {
+		var actions []string
+		node := &mocks.Node{
+			BanPeerFunc: func(_ context.Context, peer string) error {
+				actions = append(actions, "ban "+peer)
+				return nil
+			},
+			UnbanPeerFunc: func(_ context.Context, peer string) error {
+				actions = append(actions, "unban "+peer)
+				return nil
+			},
+		}
+		applied, err := ReapplyAlerts(ctx, newTestPool(node), node, opts...)
+		ts.Require().NoError(err)
+		ts.Equal(2, applied)
+		ts.Equal([]string{"ban 5.6.7.8", "unban 1.2.3.4"}, actions)
+	})
+
+	ts.Run("stops at the first failed action", func() {
+		node := &mocks.Node{BanPeerFunc: func(context.Context, string) error {
+			return errTestReapply
+		}}
+		pool := newTestPool(node)
+		applied, err := ReapplyAlerts(ctx, pool, node, opts...)
+		ts.Require().ErrorIs(err, errTestReapply)
+		ts.Equal(0, applied)
+
+		// The failure opened the circuit of the node: the alerts are not applied until it closes
+		ts.Equal(1, pool.Health()[0].ConsecutiveFailures)
+		_, err = ReapplyAlerts(ctx, pool, node, opts...)
+		ts.Require().ErrorIs(err, config.ErrNodeUnavailable)
+	})
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"context"
+	"slices"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// Verification results of an alert (empty: the alert has no effect on the node to check)
+const (
+	VerificationSuperseded = "superseded" // A later alert changed the same thing on the node (not checked anymore)
+	VerificationUnverified = "unverified" // The effect was not found on the node
+	VerificationVerified   = "verified"   // The effect was found on the node
+)
+
+// AlertMessageVerifyInterface is implemented by alert messages whose action can be checked on the node
+type AlertMessageVerifyInterface interface {
+	AlertMessageInterface
+
+	// Targets are what the action changes on the node (peer:<peer>, block:<hash>, fund:<txid>:<vout> or
+	// confiscation:<txid>), a later alert with the same targets supersedes the alert
+	Targets() []string
+
+	// Verify will query the node and return true if the effect of the action is there
+	Verify(ctx context.Context) (bool, error)
+}
+
+// VerifyAlertsResult is the result of checking the processed alerts against the node
+type VerifyAlertsResult struct {
+	Disappeared []uint32 // Sequence numbers of the verified alerts whose effect is gone (processed again)
+	Failed      int      // Alerts that could not be checked (e.g. the node is unavailable)
+	Superseded  int      // Alerts replaced by a later alert
+	Unverified  int      // Alerts whose effect was never found
+	Verified    int      // Alerts whose effect was found
+}
+
+// verifyAlert will check the effect of the alert on the node and set its verification result
+func verifyAlert(ctx context.Context, alert *AlertMessage, am AlertMessageVerifyInterface) error {
+	verified, err := am.Verify(ctx)
+	if err != nil {
+		return err
+	}
+	alert.Verification = VerificationUnverified
+	if verified {
+		alert.Verification = VerificationVerified
+	}
+	return nil
+}
+
+// nodeAlert is an alert with an action on the node
+type nodeAlert struct {
+	alert      *AlertMessage
+	message    AlertMessageVerifyInterface
+	superseded bool // A later alert changed the same targets
+}
+
+// getNodeAlerts returns the alerts with an action on the node (in sequence order), and whether a later
+// alert changed the same targets (an unban replaces the ban of the same peer)
+func getNodeAlerts(ctx context.Context, opts ...model.Options) ([]*nodeAlert, error) {
+	alerts, err := GetAllAlerts(ctx, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeAlerts := make([]*nodeAlert, 0)
+	changed := make(map[string]bool) // Targets of the later alerts
+	for i := len(alerts) - 1; i >= 0; i-- {
+		alert := alerts[i]
+		alert.SetOptions(opts...)
+		if err = alert.ReadRaw(); err != nil {
+			continue
+		}
+		am, ok := alert.ProcessAlertMessage().(AlertMessageVerifyInterface)
+		if !ok {
+			continue
+		} else if err = am.Read(alert.GetRawMessage()); err != nil {
+			continue
+		}
+		superseded := true
+		for _, target := range am.Targets() {
+			superseded = superseded && changed[target]
+			changed[target] = true
+		}
+		nodeAlerts = append(nodeAlerts, &nodeAlert{alert: alert, message: am, superseded: superseded})
+	}
+	slices.Reverse(nodeAlerts)
+	return nodeAlerts, nil
+}
+
+// VerifyAlerts will check the effect of every processed alert on the node (newest first), and mark the
+// alerts whose effect disappeared (e.g. after a node resync) as not processed, so the alert processing
+// applies them again
+//
+// An alert is only checked while no later alert changed the same targets, and only an alert that was
+// verified before is processed again: an alert whose effect was never found stays unverified
+func VerifyAlerts(ctx context.Context, opts ...model.Options) (*VerifyAlertsResult, error) {
+	nodeAlerts, err := getNodeAlerts(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyAlertsResult{}
+	for i := len(nodeAlerts) - 1; i >= 0; i-- {
+		alert := nodeAlerts[i].alert
+
+		// Skip the alerts that were replaced (or are not applied yet)
+		previous := alert.Verification
+		if nodeAlerts[i].superseded {
+			result.Superseded++
+			if previous != VerificationSuperseded {
+				alert.Verification = VerificationSuperseded
+				if err = alert.Save(ctx); err != nil {
+					return result, err
+				}
+			}
+			continue
+		} else if !alert.Processed {
+			continue
+		}
+
+		if err = verifyAlert(ctx, alert, nodeAlerts[i].message); err != nil {
+			alert.Config().Services.Log.Debugf("failed to verify alert %d: %s", alert.SequenceNumber, err.Error())
+			result.Failed++
+			continue
+		}
+		switch {
+		case alert.Verification == VerificationVerified:
+			result.Verified++
+		case previous == VerificationVerified:
+			alert.Config().Services.Log.Warnf("the effect of alert %d is not on the node anymore, processing it again", alert.SequenceNumber)
+			alert.Processed = false
+			result.Disappeared = append(result.Disappeared, alert.SequenceNumber)
+		default:
+			result.Unverified++
+		}
+		if alert.Verification != previous || !alert.Processed {
+			if err = alert.Save(ctx); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config/mocks"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// newTestPeerAlert will create a ban (or unban) peer alert
+func newTestPeerAlert(opts []model.Options, alertType AlertType, sequence uint32, peer string) *AlertMessage {
+	alert := NewAlertMessage(append(opts, model.New())...)
+	alert.SetAlertType(alertType)
+	alert.SetVersion(1)
+	alert.SetTimestamp(uint64(time.Now().Unix()))
+	message := append([]byte{byte(len(peer))}, peer...)
+	alert.SetRawMessage(append(message, 0x04, 't', 'e', 's', 't'))
+	alert.SequenceNumber = sequence
+	alert.SerializeData()
+	_ = alert.Serialize()
+	return alert
+}
+
+// TestVerifyAlerts will test the verification of the alerts with ApplyAlert() and VerifyAlerts()
+func (ts *TestSuite) TestVerifyAlerts() {
+	ctx := context.Background()
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+
+	banned := true
+	var verifyErr error
+	ts.Dependencies.Services.Node = &mocks.Node{IsPeerBannedFunc: func(context.Context, string) (bool, error) {
+		return banned, verifyErr
+	}}
+
+	apply := func(alert *AlertMessage) {
+		am := alert.ProcessAlertMessage()
+		ts.Require().NoError(am.Read(alert.GetRawMessage()))
+		ts.Require().NoError(ApplyAlert(ctx, alert, am))
+	}
+	saved := func(sequence uint32) *AlertMessage {
+		alert, err := GetAlertMessageBySequenceNumber(ctx, sequence, opts...)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(alert)
+		return alert
+	}
+
+	ts.Run("the effect is verified after the action", func() {
+		alert := newTestPeerAlert(opts, AlertTypeBanPeer, 1, "1.2.3.4")
+		apply(alert)
+		ts.True(alert.Processed)
+		ts.Equal(VerificationVerified, saved(1).Verification)
+
+		result, err := VerifyAlerts(ctx, opts...)
+		ts.Require().NoError(err)
+		ts.Equal(1, result.Verified)
+		ts.Empty(result.Disappeared)
+	})
+
+	ts.Run("a failed check does not change the alert", func() {
+		verifyErr = errors.New("connection refused")
+		result, err := VerifyAlerts(ctx, opts...)
+		verifyErr = nil
+		ts.Require().NoError(err)
+		ts.Equal(1, result.Failed)
+		ts.Equal(VerificationVerified, saved(1).Verification)
+		ts.True(saved(1).Processed)
+	})
+
+	ts.Run("an alert whose effect disappeared is processed again", func() {
+		banned = false
+		result, err := VerifyAlerts(ctx, opts...)
+		ts.Require().NoError(err)
+		ts.Equal([]uint32{1}, result.Disappeared)
+		ts.False(saved(1).Processed)
+		ts.Equal(VerificationUnverified, saved(1).Verification)
+
+		// Not verified anymore: not processed again by the next check
+		result, err = VerifyAlerts(ctx, opts...)
+		ts.Require().NoError(err)
+		ts.Empty(result.Disappeared)
+	})
+
+	ts.Run("a later alert supersedes the alert", func() {
+		apply(newTestPeerAlert(opts, AlertTypeUnbanPeer, 2, "1.2.3.4"))
+		ts.Equal(VerificationVerified, saved(2).Verification)
+
+		result, err := VerifyAlerts(ctx, opts...)
+		ts.Require().NoError(err)
+		ts.Equal(1, result.Verified)
+		ts.Equal(1, result.Superseded)
+		ts.Equal(VerificationSuperseded, saved(1).Verification)
+	})
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/mrz1836/go-datastore"
+)
+
+// TxModel is a model that can be saved with a datastore transaction
+type TxModel interface {
+	BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error)
+}
+
+// AlertMessageTxInterface is implemented by alert messages whose action only changes the datastore
+type AlertMessageTxInterface interface {
+	AlertMessageInterface
+	Changes(ctx context.Context) ([]TxModel, error)
+}
+
+// ApplyAlert will perform the alert action and save the alert
+//
+// The datastore changes of the action (e.g. the key set of a set keys alert) are saved with
+// the alert in a single transaction, so they can never be partially applied. If the action
+// fails, the alert is saved as not processed (and retried by the alert processing). After an
+// action on the node, the node is queried to check the effect (see AlertMessageVerifyInterface). An alert
+// that arrives after its enforcement window ended is logged (and still performed).
+func ApplyAlert(ctx context.Context, alert *AlertMessage, am AlertMessageInterface) error {
+	alert.Processed = true
+
+	// Actions on the node are performed (and checked) before saving the alert
+	txAlert, ok := am.(AlertMessageTxInterface)
+	if !ok {
+		if enforced, isEnforced := am.(AlertMessageEnforcementInterface); isEnforced {
+			warnExpiredAlert(alert, enforced)
+		}
+		if err := am.Do(ctx); err != nil {
+			alert.Config().Services.Log.Errorf("failed to process alert %d; err: %v", alert.SequenceNumber, err.Error())
+			alert.Processed = false
+		} else if verifyAlertMessage, canVerify := am.(AlertMessageVerifyInterface); canVerify {
+			if err = verifyAlert(ctx, alert, verifyAlertMessage); err != nil {
+				alert.Config().Services.Log.Warnf("failed to verify alert %d; err: %v", alert.SequenceNumber, err.Error())
+				alert.Verification = VerificationUnverified
+			} else if alert.Verification != VerificationVerified {
+				alert.Config().Services.Log.Warnf("the effect of alert %d was not found on the node", alert.SequenceNumber)
+			}
+		}
+		return alert.Save(ctx)
+	}
+
+	// Save the changes and the alert together
+	changes, err := txAlert.Changes(ctx)
+	if err == nil {
+		if err = SaveWithTx(ctx, alert.Datastore(), append(changes, alert)...); err == nil {
+			return nil
+		}
+	}
+	alert.Config().Services.Log.Errorf("failed to process alert %d; err: %v", alert.SequenceNumber, err.Error())
+	alert.Processed = false
+	return alert.Save(ctx)
+}
+
+// SaveWithTx will save the models in a single datastore transaction (rolled back on error)
+func SaveWithTx(ctx context.Context, ds datastore.ClientInterface, models ...TxModel) error {
+	if ds == nil {
+		return model.ErrMissingDatastore
+	}
+	return model.NewTx(ctx, ds, func(ctx context.Context, tx *datastore.Transaction) error {
+		modelsToSave := make([]model.BaseInterface, 0, len(models))
+		for _, m := range models {
+			saved, err := m.BeginSaveWithTx(ctx, tx)
+			if err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			modelsToSave = append(modelsToSave, saved...)
+		}
+		return model.CompleteSaveWithTx(ctx, tx, modelsToSave)
+	})
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+)
+
+// TestApplyAlert will test the method ApplyAlert()
+func (ts *TestSuite) TestApplyAlert() {
+	ctx := context.Background()
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+	ts.Require().NoError(CreateGenesisAlert(ctx, opts...))
+
+	ts.Run("set keys alert is saved with the keys", func() {
+		alert := newTestSetKeysAlert(ts.T(), opts, 1)
+		am := alert.ProcessAlertMessage()
+		ts.Require().NoError(am.Read(alert.GetRawMessage()))
+		ts.Require().NoError(ApplyAlert(ctx, alert, am))
+		ts.True(alert.Processed)
+
+		saved, err := GetAlertMessageBySequenceNumber(ctx, 1, opts...)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(saved)
+		ts.True(saved.Processed)
+
+		var active []*PublicKey
+		active, err = GetActivePublicKey(ctx, nil, opts...)
+		ts.Require().NoError(err)
+		keys := make([]string, 0, len(active))
+		for _, pk := range active {
+			keys = append(keys, pk.Key)
+			ts.Equal(alert.Hash, pk.LastUpdateHash)
+		}
+		ts.ElementsMatch([]string{utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5}, keys)
+
+		var epoch *KeySetEpoch
+		epoch, err = GetKeySetEpochByStartSequence(ctx, 1, opts...)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(epoch)
+		ts.Equal(alert.Hash, epoch.Hash)
+	})
+
+	ts.Run("other alerts are saved", func() {
+		alert := newTestSignedAlert(ts.T(), opts, 2)
+		am := alert.ProcessAlertMessage()
+		ts.Require().NoError(am.Read(alert.GetRawMessage()))
+		ts.Require().NoError(ApplyAlert(ctx, alert, am))
+
+		saved, err := GetAlertMessageBySequenceNumber(ctx, 2, opts...)
+		ts.Require().NoError(err)
+		ts.Require().NotNil(saved)
+		ts.Equal(alert.Hash, saved.Hash)
+	})
+}
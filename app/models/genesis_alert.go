@@ -22,7 +22,6 @@ func CreateGenesisAlert(ctx context.Context, opts ...model.Options) error {
 
 	// Create the array of keys
 	keys := newAlert.Config().GenesisKeys
-	var msg []byte
 
 	// Create the array of keys to save
 	keysToSave := make([]*PublicKey, 0, len(keys))
@@ -40,13 +39,7 @@ func CreateGenesisAlert(ctx context.Context, opts ...model.Options) error {
 	}
 
 	// Sync creating a new alert
-
-	newAlert.SetAlertType(AlertTypeSetKeys)
-	newAlert.message = msg
-	newAlert.SequenceNumber = 0
-	newAlert.timestamp = uint64(time.Date(2923, time.November, 1, 1, 1, 1, 1, time.UTC).Unix())
-	newAlert.version = 1
-	newAlert.Processed = true
+	setGenesisFields(newAlert)
 
 	// Serialize the data
 	newAlert.SerializeData()
@@ -66,6 +59,30 @@ func CreateGenesisAlert(ctx context.Context, opts ...model.Options) error {
 	}
 	_ = newAlert.Serialize()
 
+	// Save the genesis key set
+	if err = SaveKeySetEpoch(ctx, 0, newAlert.Hash, keys, model.WithAllDependencies(newAlert.Config())); err != nil {
+		return err
+	}
+
 	// Save the alert
 	return newAlert.Save(ctx)
 }
+
+// GenesisAlertHash returns the hash of the genesis alert (the keys come from the configuration,
+// so the hash is the same on every network)
+func GenesisAlertHash() string {
+	genesis := &AlertMessage{}
+	setGenesisFields(genesis)
+	genesis.SerializeData()
+	return genesis.Hash
+}
+
+// setGenesisFields will set the fields of the genesis alert
+func setGenesisFields(m *AlertMessage) {
+	m.SetAlertType(AlertTypeSetKeys)
+	m.message = nil
+	m.SequenceNumber = 0
+	m.timestamp = uint64(time.Date(2923, time.November, 1, 1, 1, 1, 1, time.UTC).Unix())
+	m.version = 1
+	m.Processed = true
+}
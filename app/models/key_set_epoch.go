@@ -0,0 +1,234 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrKeySetEpochExists is returned when another set keys alert already activated a key set at the sequence
+var ErrKeySetEpochExists = errors.New("a different key set was already activated at the sequence")
+
+// KeySetEpoch is an object representing a key set activated by a set keys alert
+//
+// An alert is signed by the key set with the latest start sequence before its own sequence,
+// so any historical alert can be validated without replaying the chain.
+type KeySetEpoch struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID            uint64 `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	StartSequence uint32 `json:"start_sequence" toml:"start_sequence" yaml:"start_sequence" bson:"start_sequence" gorm:"<-;type:int8;uniqueIndex;comment:This is the sequence of the set keys alert"`
+	Hash          string `json:"hash" toml:"hash" yaml:"hash" bson:"hash" gorm:"<-;type:char(64);index;comment:This is the hash of the set keys alert"`
+	Keys          string `json:"keys" toml:"keys" yaml:"keys" bson:"keys" gorm:"<-;type:text;comment:This is the comma separated list of public keys"`
+}
+
+// NewKeySetEpoch creates a new key set epoch
+func NewKeySetEpoch(opts ...model.Options) *KeySetEpoch {
+	return &KeySetEpoch{
+		Model: *model.NewBaseModel(model.NameKeySetEpoch, opts...),
+	}
+}
+
+// Name will get the name of the model
+func (m *KeySetEpoch) Name() string {
+	return model.NameKeySetEpoch.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *KeySetEpoch) GetTableName() string {
+	return model.TableKeySetEpochs
+}
+
+// GetModelTableName will get the collection name of the model (MongoDB)
+func (m *KeySetEpoch) GetModelTableName() string {
+	return model.TableKeySetEpochs
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *KeySetEpoch) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "start_sequence", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "hash", Value: 1}}},
+	}
+}
+
+// GetID will get the model ID
+func (m *KeySetEpoch) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *KeySetEpoch) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *KeySetEpoch) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableKeySetEpochs), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *KeySetEpoch) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *KeySetEpoch) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// PublicKeys returns the public keys (hex) of the key set
+func (m *KeySetEpoch) PublicKeys() []string {
+	if len(m.Keys) == 0 {
+		return nil
+	}
+	return strings.Split(m.Keys, ",")
+}
+
+// SetPublicKeys sets the public keys (hex) of the key set
+func (m *KeySetEpoch) SetPublicKeys(keys []string) {
+	m.Keys = strings.Join(keys, ",")
+}
+
+// GetKeySetEpochByStartSequence will get the key set activated by the alert with the given sequence
+func GetKeySetEpochByStartSequence(ctx context.Context, startSequence uint32, opts ...model.Options) (*KeySetEpoch, error) {
+
+	// Get the record
+	epoch := NewKeySetEpoch(opts...)
+	conditions := map[string]interface{}{
+		"start_sequence": startSequence,
+	}
+	if err := model.Get(
+		ctx, epoch, conditions, model.DefaultDatabaseReadTimeout, true,
+	); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return epoch, nil
+}
+
+// GetKeySetEpochForSequence will get the key set that signs the alert with the given sequence
+// (the latest key set activated before it)
+func GetKeySetEpochForSequence(ctx context.Context, sequenceNumber uint32, opts ...model.Options) (*KeySetEpoch, error) {
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+		"start_sequence": map[string]interface{}{
+			utils.LessThanCondition: sequenceNumber,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		Page:          1,
+		PageSize:      1,
+		OrderByField:  "start_sequence",
+		SortDirection: utils.SortDescending,
+	}
+
+	// Get the record
+	modelItems := make([]*KeySetEpoch, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameKeySetEpoch, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	} else if len(modelItems) == 0 {
+		return nil, nil
+	}
+
+	return modelItems[0], nil
+}
+
+// SaveKeySetEpoch will save the key set activated by a set keys alert (see keySetEpochChange)
+func SaveKeySetEpoch(ctx context.Context, startSequence uint32, hash string, keys []string,
+	opts ...model.Options) error {
+
+	epoch, err := keySetEpochChange(ctx, startSequence, hash, keys, opts...)
+	if err != nil {
+		return err
+	}
+	return epoch.Save(ctx)
+}
+
+// keySetEpochChange will create the key set activated by a set keys alert, without saving it
+//
+// A key set is never changed: the same alert (processed again) gets the saved key set, and another
+// alert at the sequence returns ErrKeySetEpochExists.
+func keySetEpochChange(ctx context.Context, startSequence uint32, hash string, keys []string,
+	opts ...model.Options) (*KeySetEpoch, error) {
+
+	epoch, err := GetKeySetEpochByStartSequence(ctx, startSequence, opts...)
+	if err != nil {
+		return nil, err
+	} else if epoch != nil {
+		if epoch.Hash != hash || epoch.Keys != strings.Join(keys, ",") {
+			return nil, fmt.Errorf("%w: %d", ErrKeySetEpochExists, startSequence)
+		}
+		return epoch, nil
+	}
+	epoch = NewKeySetEpoch(append(opts, model.New())...)
+	epoch.StartSequence = startSequence
+	epoch.Hash = hash
+	epoch.SetPublicKeys(keys)
+	return epoch, nil
+}
+
+// BackfillKeySetEpochs will create the key set history from the saved alerts if it does not exist yet
+//
+// Datastores created before key sets were tracked only have the active keys, the history is
+// rebuilt from the genesis keys and every saved set keys alert.
+func BackfillKeySetEpochs(ctx context.Context, opts ...model.Options) error {
+	genesis, err := GetKeySetEpochByStartSequence(ctx, 0, opts...)
+	if err != nil {
+		return err
+	} else if genesis != nil {
+		return nil
+	}
+
+	// The genesis keys come from the configuration
+	conf := NewKeySetEpoch(opts...).Config()
+	if err = SaveKeySetEpoch(ctx, 0, GenesisAlertHash(), conf.GenesisKeys, opts...); err != nil {
+		return err
+	}
+
+	// Every saved set keys alert
+	var alerts []*AlertMessage
+	if alerts, err = GetAllAlerts(ctx, nil, opts...); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		if alert.SequenceNumber == 0 {
+			continue
+		}
+		alert.SetOptions(opts...)
+		if err = alert.ReadRaw(); err != nil {
+			return err
+		} else if alert.GetAlertType() != AlertTypeSetKeys {
+			continue
+		}
+		setKeys := &AlertMessageSetKeys{}
+		if err = setKeys.Read(alert.GetRawMessage()); err != nil {
+			return err
+		}
+		if err = SaveKeySetEpoch(ctx, alert.SequenceNumber, alert.Hash, setKeys.PublicKeys(), opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSignedAlert will create an informational alert signed with the test genesis keys
+func newTestSignedAlert(t *testing.T, opts []model.Options, sequence uint32) *AlertMessage {
+	alert := NewAlertMessage(append(opts, model.New())...)
+	alert.SetAlertType(AlertTypeInformational)
+	alert.SetVersion(1)
+	alert.SetTimestamp(uint64(time.Now().Unix()))
+	alert.SetRawMessage([]byte{0x04, 't', 'e', 's', 't'})
+	alert.SequenceNumber = sequence
+	alert.SerializeData()
+
+	sigs, err := utils.SignWithKeys(alert.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key3})
+	require.NoError(t, err)
+	alert.SetSignatures(sigs)
+	_ = alert.Serialize()
+	return alert
+}
+
+// newTestSetKeysAlert will create a set keys alert (setting the main keys) signed with the test genesis keys
+func newTestSetKeysAlert(t *testing.T, opts []model.Options, sequence uint32) *AlertMessage {
+	alert := NewAlertMessage(append(opts, model.New())...)
+	alert.SetAlertType(AlertTypeSetKeys)
+	alert.SetVersion(1)
+	alert.SetTimestamp(uint64(time.Now().Unix()))
+	alert.SequenceNumber = sequence
+	message := make([]byte, 0, 165)
+	for _, key := range []string{utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5} {
+		b, err := hex.DecodeString(key)
+		require.NoError(t, err)
+		message = append(message, b...)
+	}
+	alert.SetRawMessage(message)
+	alert.SerializeData()
+
+	sigs, err := utils.SignWithKeys(alert.GetRawData(), []string{utils.Key1, utils.Key2, utils.Key3})
+	require.NoError(t, err)
+	alert.SetSignatures(sigs)
+	_ = alert.Serialize()
+	return alert
+}
+
+// TestKeySetEpoch_PublicKeys will test the methods PublicKeys() and SetPublicKeys()
+func TestKeySetEpoch_PublicKeys(t *testing.T) {
+	t.Parallel()
+
+	epoch := NewKeySetEpoch()
+	assert.Nil(t, epoch.PublicKeys())
+
+	epoch.SetPublicKeys([]string{utils.MainKey1, utils.MainKey2})
+	assert.Equal(t, utils.MainKey1+","+utils.MainKey2, epoch.Keys)
+	assert.Equal(t, []string{utils.MainKey1, utils.MainKey2}, epoch.PublicKeys())
+}
+
+// TestKeySetEpoch will test saving and getting key set epochs
+func (ts *TestSuite) TestKeySetEpoch() {
+	ctx := context.Background()
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+
+	// The genesis alert creates the first key set
+	ts.Require().NoError(CreateGenesisAlert(ctx, opts...))
+	genesis, err := GetKeySetEpochByStartSequence(ctx, 0, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(genesis)
+	ts.Equal(GenesisAlertHash(), genesis.Hash)
+	ts.Equal(ts.Dependencies.GenesisKeys, genesis.PublicKeys())
+
+	// Rotate the keys at sequence 5 (saving the same key set again does nothing, another one is an error)
+	ts.Require().NoError(SaveKeySetEpoch(ctx, 5, "hash5", []string{utils.MainKey1, utils.MainKey2}, opts...))
+	ts.Require().NoError(SaveKeySetEpoch(ctx, 5, "hash5", []string{utils.MainKey1, utils.MainKey2}, opts...))
+	ts.Require().ErrorIs(SaveKeySetEpoch(ctx, 5, "replayed", []string{utils.Key1}, opts...), ErrKeySetEpochExists)
+
+	ts.Run("key set for a sequence", func() {
+		var epoch *KeySetEpoch
+		for sequence, start := range map[uint32]uint32{1: 0, 5: 0, 6: 5, 100: 5} {
+			epoch, err = GetKeySetEpochForSequence(ctx, sequence, opts...)
+			ts.Require().NoError(err)
+			ts.Require().NotNil(epoch)
+			ts.Equal(start, epoch.StartSequence)
+		}
+
+		epoch, err = GetKeySetEpochForSequence(ctx, 6, opts...)
+		ts.Require().NoError(err)
+		ts.Equal([]string{utils.MainKey1, utils.MainKey2}, epoch.PublicKeys())
+
+		epoch, err = GetKeySetEpochForSequence(ctx, 0, opts...)
+		ts.Require().NoError(err)
+		ts.Nil(epoch)
+	})
+
+	ts.Run("historical alerts are validated against their key set", func() {
+		valid, validErr := newTestSignedAlert(ts.T(), opts, 3).AreHistoricalSignaturesValid(ctx)
+		ts.Require().NoError(validErr)
+		ts.True(valid)
+
+		valid, validErr = newTestSignedAlert(ts.T(), opts, 7).AreHistoricalSignaturesValid(ctx)
+		ts.Require().NoError(validErr)
+		ts.False(valid)
+	})
+
+	ts.Run("new alerts are validated against the latest key set", func() {
+		// An old sequence signed with the retired keys
+		valid, validErr := newTestSignedAlert(ts.T(), opts, 3).AreSignaturesValid(ctx)
+		ts.Require().NoError(validErr)
+		ts.False(valid)
+	})
+}
+
+// TestBackfillKeySetEpochs will test the method BackfillKeySetEpochs()
+func (ts *TestSuite) TestBackfillKeySetEpochs() {
+	ctx := context.Background()
+	opts := []model.Options{model.WithAllDependencies(ts.Dependencies)}
+
+	// Save a set keys alert without a key set (as before key sets were tracked)
+	alert := newTestSetKeysAlert(ts.T(), opts, 1)
+	ts.Require().NoError(alert.Save(ctx))
+
+	// Backfill (twice, the second run does nothing)
+	ts.Require().NoError(BackfillKeySetEpochs(ctx, opts...))
+	ts.Require().NoError(BackfillKeySetEpochs(ctx, opts...))
+
+	genesis, err := GetKeySetEpochByStartSequence(ctx, 0, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(genesis)
+	ts.Equal(ts.Dependencies.GenesisKeys, genesis.PublicKeys())
+
+	epoch, err := GetKeySetEpochByStartSequence(ctx, 1, opts...)
+	ts.Require().NoError(err)
+	ts.Require().NotNil(epoch)
+	ts.Equal(alert.Hash, epoch.Hash)
+	ts.Equal([]string{utils.MainKey1, utils.MainKey2, utils.MainKey3, utils.MainKey4, utils.MainKey5}, epoch.PublicKeys())
+}
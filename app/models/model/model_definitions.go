@@ -13,6 +13,10 @@ const (
 	NameAlertMessage Name = "alert_message" // AlertMessage is the alert message model
 	NameEmpty        Name = "empty"         // Empty model (base model without a name set)
 	NamePublicKey    Name = "public_key"    // PublicKey is the public key model
+
+	NameKeySetEpoch     Name = "key_set_epoch"    // KeySetEpoch is the key set history model
+	NameSchemaMigration Name = "schema_migration" // SchemaMigration is the applied migration model
+	NameWebhookDelivery Name = "webhook_delivery" // WebhookDelivery is the webhook outbox model
 )
 
 // All base model table names
@@ -20,4 +24,8 @@ const (
 	TableAlertMessages = "alert_messages" // TableAlertMessages is the alert message table
 	TableEmpty         = "empty"          // TableEmpty is the empty placeholder table
 	TablePublicKeys    = "public_keys"    // TablePublicKeys is the public key table
+
+	TableKeySetEpochs      = "key_set_epochs"     // TableKeySetEpochs is the key set history table
+	TableSchemaMigrations  = "schema_migrations"  // TableSchemaMigrations is the applied migration table
+	TableWebhookDeliveries = "webhook_deliveries" // TableWebhookDeliveries is the webhook outbox table
 )
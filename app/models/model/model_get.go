@@ -21,6 +21,13 @@ func Get(
 		timeout = DefaultDatabaseReadTimeout
 	}
 
+	// MongoDB documents are found by the conditions only (not the model fields)
+	if isMongo(model.Datastore()) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return getMongoModel(ctx, model, conditions)
+	}
+
 	// Attempt to Get the model (by model fields and given conditions)
 	return model.Datastore().GetModel(ctx, model, conditions, timeout, forceWriteDB)
 }
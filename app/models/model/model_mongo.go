@@ -0,0 +1,132 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDB specific names
+const (
+	mongoCountersCollection = "counters" // Holds the last ID of every collection
+	mongoIDField            = "_id"      // ID of every document
+	mongoSequenceField      = "sequence" // Last ID in the counter document
+	sqlIDField              = "id"       // ID field used in the conditions
+)
+
+// isMongo returns true if the datastore is MongoDB
+func isMongo(ds datastore.ClientInterface) bool {
+	return ds != nil && ds.Engine() == datastore.MongoDB
+}
+
+// saveMongoModel will save the model into MongoDB
+//
+// The models use numeric IDs (auto increment in SQL), so new documents get the next ID of the
+// collection from the counters collection and existing documents are replaced by ID (datastore.ErrNoResults
+// if the document does not exist).
+func saveMongoModel(ctx context.Context, model BaseInterface) error {
+	ds := model.Datastore()
+	collection := ds.GetMongoCollection(model.GetTableName())
+
+	// Update the existing document
+	if !model.IsNew() {
+		result, err := collection.ReplaceOne(ctx, bson.M{mongoIDField: model.GetID()}, model)
+		if err != nil {
+			return err
+		} else if result.MatchedCount == 0 {
+			return fmt.Errorf("%w: %s %d", datastore.ErrNoResults, model.GetTableName(), model.GetID())
+		}
+		return nil
+	}
+
+	// Insert the new document with the next ID
+	if model.GetID() == 0 {
+		id, err := nextMongoID(ctx, ds, model.GetTableName())
+		if err != nil {
+			return err
+		}
+		setModelID(model, id)
+	}
+	if _, err := collection.InsertOne(ctx, model); mongo.IsDuplicateKeyError(err) {
+		return datastore.ErrDuplicateKey
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// newMongoTx will run the function in a MongoDB transaction: the context given to the function has the session
+// of the transaction, the transaction is committed by the function (see commitMongoTx), and aborted if the
+// function fails (or did not commit)
+//
+// Transactions need a replica set or a sharded cluster (checked when the datastore is loaded)
+func newMongoTx(ctx context.Context, ds datastore.ClientInterface, fn func(ctx context.Context, tx *datastore.Transaction) error) error {
+	client := ds.GetMongoCollection(mongoCountersCollection).Database().Client()
+	return client.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		if err := sessionContext.StartTransaction(); err != nil {
+			return err
+		}
+		// Ending the session aborts the transaction if it was not committed
+		return fn(sessionContext, &datastore.Transaction{})
+	})
+}
+
+// commitMongoTx will commit the MongoDB transaction of the context (see newMongoTx), if any
+func commitMongoTx(ctx context.Context) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		return session.CommitTransaction(ctx)
+	}
+	return nil
+}
+
+// getMongoModel will get the first document matching the conditions into the model
+func getMongoModel(ctx context.Context, model BaseInterface, conditions map[string]interface{}) error {
+	err := model.Datastore().GetMongoCollection(model.GetTableName()).FindOne(
+		ctx, mongoConditions(conditions),
+	).Decode(model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return datastore.ErrNoResults
+	}
+	return err
+}
+
+// nextMongoID will increment and return the last ID of the collection
+func nextMongoID(ctx context.Context, ds datastore.ClientInterface, collectionName string) (uint64, error) {
+	var counter struct {
+		Sequence uint64 `bson:"sequence"`
+	}
+	if err := ds.GetMongoCollection(mongoCountersCollection).FindOneAndUpdate(
+		ctx,
+		bson.M{mongoIDField: collectionName},
+		bson.M{"$inc": bson.M{mongoSequenceField: 1}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter); err != nil {
+		return 0, err
+	}
+	return counter.Sequence, nil
+}
+
+// setModelID will set the ID field of the model (every model has its own ID field)
+func setModelID(model BaseInterface, id uint64) {
+	if field := reflect.Indirect(reflect.ValueOf(model)).FieldByName("ID"); field.IsValid() && field.CanSet() {
+		field.SetUint(id)
+	}
+}
+
+// mongoConditions will return the conditions as a MongoDB filter (the id field is _id)
+func mongoConditions(conditions map[string]interface{}) bson.M {
+	filter := bson.M{}
+	for field, value := range conditions {
+		if field == sqlIDField {
+			field = mongoIDField
+		}
+		filter[field] = value
+	}
+	return filter
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/mrz1836/go-datastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testMongoTable is the collection of the test model
+const testMongoTable = "test_models"
+
+// testMongoModel is a model for testing the MongoDB datastore
+type testMongoModel struct {
+	Model `bson:",inline"`
+	ID    uint64 `bson:"_id"`
+	Value string `bson:"value"`
+}
+
+// GetID will get the model ID
+func (m *testMongoModel) GetID() uint64 {
+	return m.ID
+}
+
+// GetTableName will get the database table name of the model
+func (m *testMongoModel) GetTableName() string {
+	return testMongoTable
+}
+
+// newTestMongoModel will create a test model using a MongoDB datastore on the mock deployment
+func newTestMongoModel(t *testing.T, mt *mtest.T, opts ...Options) *testMongoModel {
+	ds, err := datastore.NewClient(context.Background(), datastore.WithMongoConnection(mt.DB, "test"))
+	require.NoError(t, err)
+	conf := &config.Config{Services: config.Services{Datastore: ds}}
+	return &testMongoModel{Model: *NewBaseModel(NameEmpty, append(opts, WithAllDependencies(conf))...)}
+}
+
+// TestSaveMongoModel will test the method saveMongoModel()
+func TestSaveMongoModel(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("new model gets the next id", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt, New())
+		m.Value = "new"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+				{Key: "_id", Value: testMongoTable}, {Key: "sequence", Value: int64(7)},
+			}}),
+			mtest.CreateSuccessResponse(),
+		)
+		require.NoError(t, saveMongoModel(context.Background(), m))
+		assert.Equal(t, uint64(7), m.ID)
+
+		events := mt.GetAllStartedEvents()
+		require.Len(t, events, 2)
+		assert.Equal(t, "findAndModify", events[0].CommandName)
+		assert.Equal(t, "test_counters", events[0].Command.Lookup("findAndModify").StringValue())
+		assert.Equal(t, "insert", events[1].CommandName)
+		assert.Equal(t, "test_"+testMongoTable, events[1].Command.Lookup("insert").StringValue())
+		doc := events[1].Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, int64(7), doc.Lookup("_id").Int64())
+		assert.Equal(t, "new", doc.Lookup("value").StringValue())
+	})
+
+	mt.Run("existing model is replaced by id", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt)
+		m.ID = 3
+		m.Value = "updated"
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		require.NoError(t, saveMongoModel(context.Background(), m))
+
+		event := mt.GetStartedEvent()
+		require.NotNil(t, event)
+		assert.Equal(t, "update", event.CommandName)
+		update := event.Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, int64(3), update.Lookup("q", "_id").Int64())
+		assert.Equal(t, "updated", update.Lookup("u", "value").StringValue())
+	})
+
+	mt.Run("existing model that does not exist", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt)
+		m.ID = 4
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+		require.ErrorIs(t, saveMongoModel(context.Background(), m), datastore.ErrNoResults)
+	})
+
+	mt.Run("duplicate key", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt, New())
+		m.ID = 5
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"}))
+		require.ErrorIs(t, saveMongoModel(context.Background(), m), datastore.ErrDuplicateKey)
+	})
+}
+
+// TestGetMongoModel will test the method getMongoModel()
+func TestGetMongoModel(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.test_"+testMongoTable, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: int64(9)}, {Key: "value", Value: "found"},
+		}))
+		require.NoError(t, getMongoModel(context.Background(), m, map[string]interface{}{"id": uint64(9)}))
+		assert.Equal(t, uint64(9), m.ID)
+		assert.Equal(t, "found", m.Value)
+		assert.NotNil(t, m.Datastore())
+
+		event := mt.GetStartedEvent()
+		require.NotNil(t, event)
+		assert.Equal(t, int64(9), event.Command.Lookup("filter", "_id").Int64())
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.test_"+testMongoTable, mtest.FirstBatch))
+		require.ErrorIs(t, getMongoModel(context.Background(), m, map[string]interface{}{"value": "missing"}), datastore.ErrNoResults)
+	})
+}
+
+// TestSave_Mongo will test the method Save() with MongoDB (the saves are in a transaction)
+func TestSave_Mongo(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("committed", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt)
+		m.ID = 3
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), mtest.CreateSuccessResponse())
+		require.NoError(t, Save(context.Background(), m))
+
+		events := mt.GetAllStartedEvents()
+		require.Len(t, events, 2)
+		assert.Equal(t, "update", events[0].CommandName)
+		assert.True(t, events[0].Command.Lookup("startTransaction").Boolean())
+		assert.Equal(t, "commitTransaction", events[1].CommandName)
+	})
+
+	mt.Run("aborted", func(mt *mtest.T) {
+		m := newTestMongoModel(t, mt, New())
+		m.ID = 5
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"}), mtest.CreateSuccessResponse())
+		require.ErrorIs(t, Save(context.Background(), m), datastore.ErrDuplicateKey)
+
+		events := mt.GetAllStartedEvents()
+		require.Len(t, events, 2)
+		assert.Equal(t, "insert", events[0].CommandName)
+		assert.Equal(t, "abortTransaction", events[1].CommandName)
+	})
+}
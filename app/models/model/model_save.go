@@ -19,7 +19,7 @@ func Save(ctx context.Context, model BaseInterface) (err error) {
 
 	// Create new Datastore transaction
 	// NOTE: we need this to be in a callback context for Mongo
-	return ds.NewTx(ctx, func(tx *datastore.Transaction) (err error) {
+	return NewTx(ctx, ds, func(ctx context.Context, tx *datastore.Transaction) (err error) {
 
 		// Fire the before hooks (parent model)
 		if model.IsNew() {
@@ -65,19 +65,14 @@ func Save(ctx context.Context, model BaseInterface) (err error) {
 		// Save all models (or fail!)
 		for index := range modelsToSave {
 			// modelsToSave[index].DebugLog(ctx, fmt.Sprintf("starting to save model: %s id: %d", modelsToSave[index].Name(), modelsToSave[index].GetID()))
-			if err = modelsToSave[index].Datastore().SaveModel(
-				ctx, modelsToSave[index], tx, modelsToSave[index].IsNew(), false,
-			); err != nil {
+			if err = saveModel(ctx, modelsToSave[index], tx); err != nil {
 				return
 			}
 		}
 
-		// Commit all the model(s) if needed
-		if tx.CanCommit() {
-			// model.DebugLog(ctx, "committing db transaction...")
-			if err = tx.Commit(); err != nil {
-				return
-			}
+		// Commit all the model(s)
+		if err = commitTx(ctx, tx); err != nil {
+			return
 		}
 
 		// Fire after hooks (only on commit success)
@@ -156,9 +151,7 @@ func BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction, model BaseI
 	// Save all models (or fail!)
 	for index := range modelsToSave {
 		// modelsToSave[index].DebugLog(ctx, fmt.Sprintf("starting to save model: %s id: %d", modelsToSave[index].Name(), modelsToSave[index].GetID()))
-		if err = modelsToSave[index].Datastore().SaveModel(
-			ctx, modelsToSave[index], tx, modelsToSave[index].IsNew(), false,
-		); err != nil {
+		if err = saveModel(ctx, modelsToSave[index], tx); err != nil {
 			return
 		}
 	}
@@ -169,12 +162,9 @@ func BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction, model BaseI
 // CompleteSaveWithTx will finish saving the model(s) into the Datastore
 func CompleteSaveWithTx(ctx context.Context, tx *datastore.Transaction, modelsToSave []BaseInterface) (err error) {
 
-	// Commit all the model(s) if needed
-	if tx.CanCommit() {
-		// modelsToSave[0].DebugLog(ctx, "committing db transaction...")
-		if err = tx.Commit(); err != nil {
-			return
-		}
+	// Commit all the model(s)
+	if err = commitTx(ctx, tx); err != nil {
+		return
 	}
 
 	// Fire after hooks (only on commit success)
@@ -198,3 +188,30 @@ func CompleteSaveWithTx(ctx context.Context, tx *datastore.Transaction, modelsTo
 
 	return
 }
+
+// NewTx will run the function in a datastore transaction, the saves must use the context and the transaction
+// given to the function (with MongoDB, the context has the session of the transaction)
+func NewTx(ctx context.Context, ds datastore.ClientInterface, fn func(ctx context.Context, tx *datastore.Transaction) error) error {
+	if isMongo(ds) {
+		return newMongoTx(ctx, ds, fn)
+	}
+	return ds.NewTx(ctx, func(tx *datastore.Transaction) error {
+		return fn(ctx, tx)
+	})
+}
+
+// commitTx will commit the transaction if needed (the MongoDB transaction is the session of the context)
+func commitTx(ctx context.Context, tx *datastore.Transaction) error {
+	if tx.CanCommit() {
+		return tx.Commit()
+	}
+	return commitMongoTx(ctx)
+}
+
+// saveModel will save a single model with the transaction (MongoDB saves use the session of the context)
+func saveModel(ctx context.Context, model BaseInterface, tx *datastore.Transaction) error {
+	if isMongo(model.Datastore()) {
+		return saveMongoModel(ctx, model)
+	}
+	return model.Datastore().SaveModel(ctx, model, tx, model.IsNew(), false)
+}
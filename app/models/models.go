@@ -17,5 +17,20 @@ var (
 		&PublicKey{
 			Model: *model.NewBaseModel(model.NamePublicKey),
 		},
+
+		// KeySetEpoch - used for the key set history
+		&KeySetEpoch{
+			Model: *model.NewBaseModel(model.NameKeySetEpoch),
+		},
+
+		// SchemaMigration - used for the applied migrations
+		&SchemaMigration{
+			Model: *model.NewBaseModel(model.NameSchemaMigration),
+		},
+
+		// WebhookDelivery - used for the webhook outbox
+		&WebhookDelivery{
+			Model: *model.NewBaseModel(model.NameWebhookDelivery),
+		},
 	}
 )
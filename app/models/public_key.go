@@ -3,10 +3,11 @@ package models
 import (
 	"context"
 
-	"github.com/bitcoin-sv/alert-system/app/config"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
 	"github.com/bitcoin-sv/alert-system/utils"
 	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // PublicKey is an object representing a public key
@@ -38,6 +39,19 @@ func (m *PublicKey) GetTableName() string {
 	return model.TablePublicKeys
 }
 
+// GetModelTableName will get the collection name of the model (MongoDB)
+func (m *PublicKey) GetModelTableName() string {
+	return model.TablePublicKeys
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *PublicKey) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}},
+		{Keys: bson.D{{Key: "active", Value: 1}}},
+	}
+}
+
 // GetID will get the model ID
 func (m *PublicKey) GetID() uint64 {
 	return m.ID
@@ -92,19 +106,3 @@ func GetActivePublicKey(ctx context.Context, metadata *model.Metadata, opts ...m
 
 	return modelItems, nil
 }
-
-// ClearActivePublicKeys will clear the active public keys
-// todo this needs to be refactored to use model update/save
-func ClearActivePublicKeys(_ context.Context, ds datastore.ClientInterface) error {
-	// Execute the query
-	tx := ds.Execute("").Exec(
-		"UPDATE "+config.DatabasePrefix+"_"+model.TablePublicKeys+" SET "+utils.FieldActive+" = ?",
-		false,
-	).Begin()
-
-	// Commit the transaction
-	tx.Commit()
-
-	// Return the error
-	return tx.Error
-}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SchemaMigration is an object representing an applied versioned migration
+type SchemaMigration struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID          uint64 `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	Version     uint32 `json:"version" toml:"version" yaml:"version" bson:"version" gorm:"<-;type:int8;uniqueIndex;comment:This is the migration version"`
+	Description string `json:"description" toml:"description" yaml:"description" bson:"description" gorm:"<-;type:text;comment:This is the migration description"`
+}
+
+// NewSchemaMigration creates a new schema migration
+func NewSchemaMigration(opts ...model.Options) *SchemaMigration {
+	return &SchemaMigration{
+		Model: *model.NewBaseModel(model.NameSchemaMigration, opts...),
+	}
+}
+
+// Name will get the name of the model
+func (m *SchemaMigration) Name() string {
+	return model.NameSchemaMigration.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *SchemaMigration) GetTableName() string {
+	return model.TableSchemaMigrations
+}
+
+// GetModelTableName will get the collection name of the model (MongoDB)
+func (m *SchemaMigration) GetModelTableName() string {
+	return model.TableSchemaMigrations
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *SchemaMigration) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "version", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+}
+
+// GetID will get the model ID
+func (m *SchemaMigration) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *SchemaMigration) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *SchemaMigration) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableSchemaMigrations), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *SchemaMigration) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *SchemaMigration) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// GetSchemaMigrations will get all applied migrations (in version order)
+func GetSchemaMigrations(ctx context.Context, opts ...model.Options) ([]*SchemaMigration, error) {
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  "version",
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*SchemaMigration, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameSchemaMigration, &modelItems, nil, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/utils"
+	"github.com/mrz1836/go-datastore"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WebhookDeliveryStatus is the state of a webhook delivery in the outbox
+type WebhookDeliveryStatus string
+
+// Webhook delivery states
+const (
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"      // Max attempts reached, waiting for a manual replay
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered" // Successfully delivered
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"   // Waiting for the next attempt
+)
+
+// WebhookDelivery is an object representing a webhook notification in the outbox
+type WebhookDelivery struct {
+	// Base model
+	model.Model `bson:",inline"`
+
+	// Model specific fields
+	ID             uint64                `json:"id" toml:"id" yaml:"id" bson:"_id" gorm:"primaryKey;comment:This is a unique identifier"`
+	AlertHash      string                `json:"alert_hash" toml:"alert_hash" yaml:"alert_hash" bson:"alert_hash" gorm:"<-;type:char(64);index;comment:This is the hash of the alert"`
+	SequenceNumber uint32                `json:"sequence_number" toml:"sequence_number" yaml:"sequence_number" bson:"sequence_number" gorm:"<-;type:int8;index;comment:This is the alert sequence number"`
+	Endpoint       string                `json:"endpoint" toml:"endpoint" yaml:"endpoint" bson:"endpoint" gorm:"<-;type:varchar(64);index;comment:This is the name of the webhook endpoint"`
+	URL            string                `json:"url" toml:"url" yaml:"url" bson:"url" gorm:"<-;type:text;comment:This is the webhook URL"`
+	Payload        string                `json:"payload" toml:"payload" yaml:"payload" bson:"payload" gorm:"<-;type:text;comment:This is the JSON payload to deliver"`
+	Status         WebhookDeliveryStatus `json:"status" toml:"status" yaml:"status" bson:"status" gorm:"<-;type:varchar(16);index;comment:This is the delivery status"`
+	Attempts       uint32                `json:"attempts" toml:"attempts" yaml:"attempts" bson:"attempts" gorm:"<-;type:int;comment:This is the number of delivery attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" toml:"next_attempt_at" yaml:"next_attempt_at" bson:"next_attempt_at" gorm:"<-;comment:This is the time of the next delivery attempt"`
+	LastError      string                `json:"last_error" toml:"last_error" yaml:"last_error" bson:"last_error" gorm:"<-;type:text;comment:This is the last delivery error"`
+}
+
+// NewWebhookDelivery creates a new webhook delivery
+func NewWebhookDelivery(opts ...model.Options) *WebhookDelivery {
+	return &WebhookDelivery{
+		Model:  *model.NewBaseModel(model.NameWebhookDelivery, opts...),
+		Status: WebhookDeliveryStatusPending,
+	}
+}
+
+// Name will get the name of the model
+func (m *WebhookDelivery) Name() string {
+	return model.NameWebhookDelivery.String()
+}
+
+// GetTableName will get the database table name of the model
+func (m *WebhookDelivery) GetTableName() string {
+	return model.TableWebhookDeliveries
+}
+
+// GetModelTableName will get the collection name of the model (MongoDB)
+func (m *WebhookDelivery) GetModelTableName() string {
+	return model.TableWebhookDeliveries
+}
+
+// GetMongoIndexes will get the MongoDB indexes of the model
+func (m *WebhookDelivery) GetMongoIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "alert_hash", Value: 1}}},
+		{Keys: bson.D{{Key: "sequence_number", Value: 1}}},
+		{Keys: bson.D{{Key: "endpoint", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+	}
+}
+
+// GetID will get the model ID
+func (m *WebhookDelivery) GetID() uint64 {
+	return m.ID
+}
+
+// Display filter the model for display
+func (m *WebhookDelivery) Display() interface{} {
+	return m
+}
+
+// Migrate will run model-specific migrations on startup
+func (m *WebhookDelivery) Migrate(client datastore.ClientInterface) error {
+	return client.IndexMetadata(client.GetTableName(model.TableWebhookDeliveries), model.MetadataField)
+}
+
+// BeginSaveWithTx will start saving the model into the Datastore with the provided transaction
+func (m *WebhookDelivery) BeginSaveWithTx(ctx context.Context, tx *datastore.Transaction) ([]model.BaseInterface, error) {
+	return model.BeginSaveWithTx(ctx, tx, m)
+}
+
+// Save will save the model into the Datastore
+func (m *WebhookDelivery) Save(ctx context.Context) error {
+	return model.Save(ctx, m)
+}
+
+// IsDue returns true if the delivery is pending and the next attempt time has passed
+func (m *WebhookDelivery) IsDue(now time.Time) bool {
+	return m.Status == WebhookDeliveryStatusPending && !m.NextAttemptAt.After(now)
+}
+
+// GetWebhookDeliveryByID will get the webhook delivery by its ID
+func GetWebhookDeliveryByID(ctx context.Context, id uint64, opts ...model.Options) (*WebhookDelivery, error) {
+
+	// Get the record
+	delivery := NewWebhookDelivery(opts...)
+	conditions := map[string]interface{}{
+		utils.FieldID: id,
+	}
+	if err := model.Get(
+		ctx, delivery, conditions, model.DefaultDatabaseReadTimeout, true,
+	); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetWebhookDeliveryByAlertHash will get a webhook delivery of the alert (nil: the alert has no deliveries)
+func GetWebhookDeliveryByAlertHash(ctx context.Context, hash string, opts ...model.Options) (*WebhookDelivery, error) {
+
+	// Get the record
+	delivery := NewWebhookDelivery(opts...)
+	conditions := map[string]interface{}{
+		"alert_hash": hash,
+	}
+	if err := model.Get(
+		ctx, delivery, conditions, model.DefaultDatabaseReadTimeout, true,
+	); err != nil {
+		if errors.Is(err, datastore.ErrNoResults) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetWebhookDeliveriesByStatus will get all webhook deliveries with the given status
+func GetWebhookDeliveriesByStatus(ctx context.Context, status WebhookDeliveryStatus, metadata *model.Metadata,
+	opts ...model.Options) ([]*WebhookDelivery, error) {
+
+	// Set the conditions
+	conditions := &map[string]interface{}{
+		utils.FieldStatus: string(status),
+		utils.FieldDeletedAt: map[string]interface{}{ // IS NULL
+			utils.ExistsCondition: false,
+		},
+	}
+
+	// Set the query params
+	queryParams := &datastore.QueryParams{
+		OrderByField:  utils.FieldID,
+		SortDirection: utils.SortAscending,
+	}
+
+	// Get the records
+	modelItems := make([]*WebhookDelivery, 0)
+	if err := model.GetModelsByConditions(
+		ctx, model.NameWebhookDelivery, &modelItems, metadata, conditions, queryParams, opts...,
+	); err != nil {
+		return nil, err
+	}
+
+	return modelItems, nil
+}
+
+// GetDueWebhookDeliveries will get all pending webhook deliveries that are due for an attempt
+func GetDueWebhookDeliveries(ctx context.Context, now time.Time, opts ...model.Options) ([]*WebhookDelivery, error) {
+	pending, err := GetWebhookDeliveriesByStatus(ctx, WebhookDeliveryStatusPending, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter here rather than in the query, time comparisons are not portable across engines
+	due := make([]*WebhookDelivery, 0, len(pending))
+	for _, delivery := range pending {
+		if delivery.IsDue(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebhookDelivery will test webhook deliveries
+func (ts *TestSuite) TestWebhookDelivery() {
+	ts.T().Run("success - no options, base model", func(t *testing.T) {
+		delivery := NewWebhookDelivery()
+		require.NotNil(t, delivery)
+		assert.Equal(t, uint64(0), delivery.GetID())
+		assert.Equal(t, WebhookDeliveryStatusPending, delivery.Status)
+		assert.Equal(t, model.NameWebhookDelivery.String(), delivery.Name())
+		assert.Equal(t, model.TableWebhookDeliveries, delivery.GetTableName())
+	})
+
+	ts.T().Run("success - is due", func(t *testing.T) {
+		now := time.Now().UTC()
+		delivery := NewWebhookDelivery()
+		delivery.NextAttemptAt = now
+		assert.True(t, delivery.IsDue(now))
+		assert.False(t, delivery.IsDue(now.Add(-time.Second)))
+
+		delivery.Status = WebhookDeliveryStatusDead
+		assert.False(t, delivery.IsDue(now))
+	})
+}
+
+// TestWebhookDelivery_GetDue will test getting due webhook deliveries
+func (ts *TestSuite) TestWebhookDelivery_GetDue() {
+	now := time.Now().UTC()
+
+	// Create one due, one backing off and one dead delivery
+	for _, d := range []struct {
+		status WebhookDeliveryStatus
+		next   time.Time
+	}{
+		{WebhookDeliveryStatusPending, now.Add(-time.Minute)},
+		{WebhookDeliveryStatusPending, now.Add(time.Hour)},
+		{WebhookDeliveryStatusDead, now.Add(-time.Minute)},
+	} {
+		delivery := NewWebhookDelivery(model.WithAllDependencies(ts.Dependencies), model.New())
+		delivery.AlertHash = testPublicKeyLastUpdateHash
+		delivery.URL = "https://webhook.example.com"
+		delivery.Payload = "{}"
+		delivery.Status = d.status
+		delivery.NextAttemptAt = d.next
+		ts.Require().NoError(delivery.Save(context.Background()))
+	}
+
+	// Get the due deliveries
+	due, err := GetDueWebhookDeliveries(context.Background(), now, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(due, 1)
+	ts.Require().Equal(uint64(1), due[0].GetID())
+
+	// Get the dead deliveries
+	var dead []*WebhookDelivery
+	dead, err = GetWebhookDeliveriesByStatus(context.Background(), WebhookDeliveryStatusDead, nil, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Len(dead, 1)
+	ts.Require().Equal(uint64(3), dead[0].GetID())
+
+	// Get a delivery by id
+	var delivery *WebhookDelivery
+	delivery, err = GetWebhookDeliveryByID(context.Background(), 2, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NotNil(delivery)
+	ts.Require().Equal(WebhookDeliveryStatusPending, delivery.Status)
+
+	// Missing delivery
+	delivery, err = GetWebhookDeliveryByID(context.Background(), 99, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Nil(delivery)
+
+	// Get a delivery by alert hash
+	delivery, err = GetWebhookDeliveryByAlertHash(context.Background(), testPublicKeyLastUpdateHash, model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().NotNil(delivery)
+	delivery, err = GetWebhookDeliveryByAlertHash(context.Background(), "missing", model.WithAllDependencies(ts.Dependencies))
+	ts.Require().NoError(err)
+	ts.Require().Nil(delivery)
+}
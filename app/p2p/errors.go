@@ -6,6 +6,7 @@ import "errors"
 var (
 	ErrAlertNotFoundBySequence = errors.New("failed to find alert by sequence in datastore")
 	ErrAlertNotLatest          = errors.New("failed to find latest alert datastore")
+	ErrAlertNotNextSequence    = errors.New("peer is sending an alert that is not the next sequence")
 	ErrInvalidAlerts           = errors.New("peer is sending invalid alerts")
 	ErrSyncFiveBytes           = errors.New("sync message is less than 5 bytes, not valid")
 	ErrSyncMessageByte         = errors.New("sync message needs at least a byte")
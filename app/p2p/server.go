@@ -10,9 +10,11 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/events"
 	"github.com/bitcoin-sv/alert-system/app/models"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
 	"github.com/bitcoin-sv/alert-system/app/webhook"
@@ -54,10 +56,16 @@ type Server struct {
 	topicNames                    []string
 	topics                        map[string]*pubsub.Topic
 	dht                           *dht.IpfsDHT
+	events                        *events.Broadcaster
 	quitAlertProcessingChannel    chan bool
+	quitAlertVerificationChannel  chan bool
+	quitChainHeightChannel        chan bool
+	quitNodeHealthChannel         chan bool
 	quitPeerDiscoveryChannel      chan bool
 	quitPeerInitializationChannel chan bool
+	quitWebhookDeliveryChannel    chan bool
 	activePeers                   int
+	synced                        atomic.Bool // The alerts were synced from a peer
 	//peers         []peer.AddrInfo
 }
 
@@ -89,7 +97,11 @@ func NewServer(o ServerOptions) (*Server, error) {
 
 	var extMultiAddr maddr.Multiaddr
 	if o.Config.P2P.BroadcastIP != "" {
-		extMultiAddr, err = maddr.NewMultiaddr(fmt.Sprintf("/ip4/%s/tcp/%s", o.Config.P2P.BroadcastIP, o.Config.P2P.Port))
+		protocol := "ip4"
+		if net.ParseIP(o.Config.P2P.BroadcastIP) == nil {
+			protocol = "dns4" // The broadcast IP can also be a domain name
+		}
+		extMultiAddr, err = maddr.NewMultiaddr(fmt.Sprintf("/%s/%s/tcp/%s", protocol, o.Config.P2P.BroadcastIP, o.Config.P2P.Port))
 		if err != nil {
 			return nil, err
 		}
@@ -179,6 +191,7 @@ func NewServer(o ServerOptions) (*Server, error) {
 		topicNames:                    o.TopicNames,
 		privateKey:                    pk,
 		config:                        o.Config,
+		events:                        events.NewBroadcaster(),
 		quitPeerInitializationChannel: make(chan bool, 1),
 	}, nil
 }
@@ -265,6 +278,10 @@ func (s *Server) Start(ctx context.Context) error {
 	// initialize the channel before use in discoverPeers is called
 	s.RunPeerDiscovery(ctx, routingDiscovery)
 	s.quitAlertProcessingChannel = s.RunAlertProcessingCron(ctx)
+	s.quitAlertVerificationChannel = s.RunAlertVerificationCron(ctx)
+	s.quitWebhookDeliveryChannel = s.RunWebhookDeliveryCron(ctx)
+	s.quitNodeHealthChannel = s.RunNodeHealthCron(ctx)
+	s.quitChainHeightChannel = s.RunChainHeightCron(ctx)
 
 	ps, err := pubsub.NewGossipSub(ctx, s.host, pubsub.WithDiscovery(routingDiscovery))
 	if err != nil {
@@ -279,6 +296,7 @@ func (s *Server) Start(ctx context.Context) error {
 			stream: stream,
 			config: s.config,
 			ctx:    ctx,
+			events: s.events,
 			peer:   stream.Conn().RemotePeer(),
 		}
 
@@ -343,6 +361,11 @@ OUTER:
 	return nil
 }
 
+// Synced returns true once the alerts were synced from a peer
+func (s *Server) Synced() bool {
+	return s.synced.Load()
+}
+
 // Connected returns true if the server is connected
 func (s *Server) Connected() bool {
 	return s.connected
@@ -355,7 +378,11 @@ func (s *Server) Stop(_ context.Context) error {
 	s.config.Services.Log.Debugf("sending signals to persistent processes...")
 	s.quitPeerDiscoveryChannel <- true
 	s.quitAlertProcessingChannel <- true
+	s.quitAlertVerificationChannel <- true
 	s.quitPeerInitializationChannel <- true
+	s.quitWebhookDeliveryChannel <- true
+	s.quitNodeHealthChannel <- true
+	s.quitChainHeightChannel <- true
 
 	s.config.Services.Log.Debugf("removing stream handler to stop allowing connections")
 	s.host.RemoveStreamHandler(protocol.ID(s.config.P2P.AlertSystemProtocolID))
@@ -368,6 +395,11 @@ func (s *Server) Stop(_ context.Context) error {
 	return s.dht.Close()
 }
 
+// Events returns the broadcaster of accepted alerts
+func (s *Server) Events() *events.Broadcaster {
+	return s.events
+}
+
 // ActivePeers returns the number of active peers
 func (s *Server) ActivePeers() int {
 	return s.activePeers
@@ -375,7 +407,8 @@ func (s *Server) ActivePeers() int {
 
 // RunAlertProcessingCron starts a cron job to attempt to retry unprocessed alerts
 func (s *Server) RunAlertProcessingCron(ctx context.Context) chan bool {
-	ticker := time.NewTicker(s.config.AlertProcessingInterval)
+	ticker := time.NewTicker(s.config.Reloadable().AlertProcessingInterval)
+	reloaded := s.config.Reloaded()
 	quit := make(chan bool, 1)
 	go func() {
 		for {
@@ -385,6 +418,9 @@ func (s *Server) RunAlertProcessingCron(ctx context.Context) chan bool {
 				if err != nil {
 					s.config.Services.Log.Errorf("error processing alerts: %v", err.Error())
 				}
+			case <-reloaded: // The interval may have changed
+				ticker.Reset(s.config.Reloadable().AlertProcessingInterval)
+				reloaded = s.config.Reloaded()
 			case <-quit:
 				s.config.Services.Log.Infof("stopping alert processing process")
 				ticker.Stop()
@@ -395,8 +431,159 @@ func (s *Server) RunAlertProcessingCron(ctx context.Context) chan bool {
 	return quit
 }
 
+// RunAlertVerificationCron starts a cron job to check the effect of the processed alerts on the node (the
+// alerts whose effect disappeared are processed again by the alert processing)
+func (s *Server) RunAlertVerificationCron(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.Reloadable().AlertVerificationInterval)
+	reloaded := s.config.Reloaded()
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				err := s.verifyAlerts(ctx)
+				if err != nil {
+					s.config.Services.Log.Errorf("error verifying alerts: %v", err.Error())
+				}
+			case <-reloaded: // The interval may have changed
+				ticker.Reset(s.config.Reloadable().AlertVerificationInterval)
+				reloaded = s.config.Reloaded()
+			case <-quit:
+				s.config.Services.Log.Infof("stopping alert verification process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// RunWebhookDeliveryCron starts a cron job to retry pending webhook deliveries
+func (s *Server) RunWebhookDeliveryCron(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.Webhook.DeliveryInterval)
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				delivered, err := webhook.ProcessDeliveries(ctx, s.config)
+				if err != nil {
+					s.config.Services.Log.Errorf("error processing webhook deliveries: %v", err.Error())
+				} else if delivered > 0 {
+					s.config.Services.Log.Infof("delivered %d pending webhooks", delivered)
+				}
+			case <-quit:
+				s.config.Services.Log.Infof("stopping webhook delivery process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// RunNodeHealthCron starts a cron job to probe the liveness of the nodes (see config.NodePool)
+func (s *Server) RunNodeHealthCron(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.Reloadable().NodeHealth.ProbeInterval)
+	reloaded := s.config.Reloaded()
+	quit := make(chan bool, 1)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if pool, ok := s.config.Services.Node.(*config.NodePool); ok {
+					pool.Probe(ctx)
+					s.reconcileNodes(ctx, pool)
+				}
+			case <-reloaded: // The interval may have changed
+				ticker.Reset(s.config.Reloadable().NodeHealth.ProbeInterval)
+				reloaded = s.config.Reloaded()
+			case <-quit:
+				s.config.Services.Log.Infof("stopping node health process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// RunChainHeightCron starts a cron job to follow the block height of the node (see config.ChainHeight), and
+// log the enforcement windows of the alerts that start or end
+func (s *Server) RunChainHeightCron(ctx context.Context) chan bool {
+	ticker := time.NewTicker(s.config.Reloadable().ChainHeightInterval)
+	reloaded := s.config.Reloaded()
+	quit := make(chan bool, 1)
+	go func() {
+		s.followChainHeight(ctx) // Known before the first alerts are processed
+		for {
+			select {
+			case <-ticker.C:
+				s.followChainHeight(ctx)
+			case <-reloaded: // The interval may have changed
+				ticker.Reset(s.config.Reloadable().ChainHeightInterval)
+				reloaded = s.config.Reloaded()
+			case <-quit:
+				s.config.Services.Log.Infof("stopping chain height process")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return quit
+}
+
+// followChainHeight will update the block height of the node, and log the enforcement windows that started
+// or ended since the previous height
+func (s *Server) followChainHeight(ctx context.Context) {
+	previous, height, err := s.config.Services.Chain.Update(ctx, s.config.Services.Node)
+	if err != nil {
+		s.config.Services.Log.Debugf("error getting the block height: %v", err.Error())
+		return
+	} else if previous < 0 || previous == height {
+		return
+	}
+
+	windows, err := models.GetEnforcementWindows(ctx, height, model.WithAllDependencies(s.config))
+	if err != nil {
+		s.config.Services.Log.Errorf("error getting the enforcement windows: %v", err.Error())
+		return
+	}
+	for _, window := range windows {
+		if window.StatusAt(previous) == window.Status {
+			continue
+		}
+		switch window.Status {
+		case models.EnforcementActive:
+			s.config.Services.Log.Infof("enforcement of %s (alert %d) started at height %d", window.Target, window.SequenceNumber, window.Start)
+		case models.EnforcementExpired:
+			s.config.Services.Log.Infof("enforcement of %s (alert %d) ended at height %d", window.Target, window.SequenceNumber, window.Stop)
+		}
+	}
+}
+
+// reconcileNodes will apply the alerts again on the nodes that were restarted or reset (see config.NodePool)
+func (s *Server) reconcileNodes(ctx context.Context, pool *config.NodePool) {
+	for _, node := range pool.CheckRestarts(ctx) {
+		applied, err := models.ReapplyAlerts(ctx, pool, node, model.WithAllDependencies(s.config))
+		if err != nil {
+			s.config.Services.Log.Errorf("error applying the alerts again after a node restart: %v", err.Error())
+			continue
+		}
+		pool.Reconciled(node)
+		s.config.Services.Log.Infof("applied %d alerts again on node %s", applied, node.GetRPCHost())
+	}
+}
+
 // processAlerts performs the alert processing
 func (s *Server) processAlerts(ctx context.Context) error {
+
+	// Wait for a node to be available (the alerts would only fail)
+	if pool, ok := s.config.Services.Node.(*config.NodePool); ok && !pool.Healthy() {
+		s.config.Services.Log.Infof("skipping alert processing, no node is available")
+		return nil
+	}
+
 	alerts, err := models.GetAllUnprocessedAlerts(ctx, nil, model.WithAllDependencies(s.config))
 	if err != nil {
 		return err
@@ -420,27 +607,42 @@ func (s *Server) processAlerts(ctx context.Context) error {
 			return err
 		}
 		s.config.Services.Log.Debugf("attempting to process alert %d of type %d", alert.SequenceNumber, alert.GetAlertType())
-		alert.Processed = true
-		if err = ak.Do(ctx); err != nil {
-			s.config.Services.Log.Errorf("failed to process alert %d; err: %v", alert.SequenceNumber, err.Error())
-			alert.Processed = false
+		if err = models.ApplyAlert(ctx, alert, ak); err != nil {
+			return err
 		}
-
 		if alert.Processed {
 			success++
-			// Save the alert
-			if err = alert.Save(ctx); err != nil {
-				return err
-			}
 		}
 	}
 	s.config.Services.Log.Infof("Processed %d failed alerts", success)
 	return nil
 }
 
+// verifyAlerts will check the effect of the processed alerts on the node (the ones that disappeared are
+// processed again by processAlerts)
+func (s *Server) verifyAlerts(ctx context.Context) error {
+
+	// Wait for a node to be available (every check would fail)
+	if pool, ok := s.config.Services.Node.(*config.NodePool); ok && !pool.Healthy() {
+		s.config.Services.Log.Infof("skipping alert verification, no node is available")
+		return nil
+	}
+
+	result, err := models.VerifyAlerts(ctx, model.WithAllDependencies(s.config))
+	if err != nil {
+		return err
+	}
+	s.config.Services.Log.Debugf(
+		"verified alerts on the node: %d verified, %d unverified, %d superseded, %d failed, %d disappeared",
+		result.Verified, result.Unverified, result.Superseded, result.Failed, len(result.Disappeared),
+	)
+	return nil
+}
+
 // RunPeerDiscovery starts a cron job to resync peers and updates routable peers
 func (s *Server) RunPeerDiscovery(ctx context.Context, routingDiscovery *drouting.RoutingDiscovery) {
-	ticker := time.NewTicker(s.config.P2P.PeerDiscoveryInterval)
+	ticker := time.NewTicker(s.config.Reloadable().PeerDiscoveryInterval)
+	reloaded := s.config.Reloaded()
 
 	// assign a quit channel before any go routines are started
 	s.quitPeerDiscoveryChannel = make(chan bool, 1)
@@ -455,6 +657,9 @@ func (s *Server) RunPeerDiscovery(ctx context.Context, routingDiscovery *droutin
 				s.config.Services.Log.Infof("stopping peer discovery process")
 				ticker.Stop()
 				return
+			case <-reloaded: // The interval may have changed
+				ticker.Reset(s.config.Reloadable().PeerDiscoveryInterval)
+				reloaded = s.config.Reloaded()
 			case <-ticker.C:
 				err := s.discoverPeers(ctx, routingDiscovery)
 				if err != nil {
@@ -577,6 +782,7 @@ OUTER:
 						t := StreamThread{
 							config:      s.config,
 							ctx:         ctx,
+							events:      s.events,
 							peer:        foundPeer.ID,
 							stream:      stream,
 							quitChannel: s.quitPeerDiscoveryChannel,
@@ -589,6 +795,7 @@ OUTER:
 						}
 
 						s.config.Services.Log.Infof("successfully synced up to %d from peer %s", t.LatestSequence(), foundPeer.ID.String())
+						s.synced.Store(true)
 
 						// Set the flag
 						connected++
@@ -683,26 +890,20 @@ func (s *Server) Subscribe(ctx context.Context, subscriber *pubsub.Subscription,
 			s.config.Services.Log.Errorf("failed to read message: %s", err.Error())
 			continue
 		}
-		ak.Processed = true
-
-		// Perform alert action
-		if err = am.Do(ctx); err != nil {
-			s.config.Services.Log.Errorf("failed to do alert action: %s", err.Error())
-			ak.Processed = false
-		}
 
-		// Save the alert message
-		if err = ak.Save(ctx); err != nil {
+		// Perform the alert action and save the alert message
+		if err = models.ApplyAlert(ctx, ak, am); err != nil {
 			s.config.Services.Log.Errorf("failed to save alert message: %s", err.Error())
 		}
 
 		s.config.Services.Log.Infof("[%s] got alert type: %d, from: %s", subscriber.Topic(), ak.GetAlertType(), msg.ReceivedFrom.String())
 
-		// Send the webhook
-		if len(s.config.AlertWebhookURL) > 0 {
-			if err = webhook.PostAlert(ctx, s.config.Services.HTTPClient, s.config.AlertWebhookURL, ak); err != nil {
-				s.config.Services.Log.Errorf("error processing webhook request: %s", err.Error())
-			}
+		// Publish the alert to the stream subscribers
+		publishAlert(ctx, s.config, s.events, ak)
+
+		// Queue the webhooks and make the first attempt (failures are retried by the delivery cron)
+		if _, err = webhook.EnqueueAndDeliver(ctx, s.config, ak); err != nil {
+			s.config.Services.Log.Errorf("error queueing webhook delivery: %s", err.Error())
 		}
 	}
 }
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/events"
 	"github.com/bitcoin-sv/alert-system/app/models"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
+	"github.com/bitcoin-sv/alert-system/app/webhook"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
@@ -25,6 +27,7 @@ type Thread interface {
 type StreamThread struct {
 	config           *config.Config
 	ctx              context.Context //nolint:containedctx // TODO should remove this, should be passed in via methods only
+	events           *events.Broadcaster
 	latestSequence   uint32
 	myLatestSequence uint32
 	peer             peer.ID
@@ -215,6 +218,12 @@ func (s *StreamThread) ProcessGotSequenceNumber(msg *SyncMessage) error {
 		return err
 	}
 
+	// Only the next alert is accepted (it is validated against the latest key set)
+	if a.SequenceNumber != s.myLatestSequence+1 {
+		s.config.Services.Log.Errorf("peer %s sent alert %d, expected %d", s.peer.String(), a.SequenceNumber, s.myLatestSequence+1)
+		return ErrAlertNotNextSequence
+	}
+
 	// Verify signatures
 	var valid bool
 	if valid, err = a.AreSignaturesValid(s.ctx); err != nil {
@@ -234,17 +243,20 @@ func (s *StreamThread) ProcessGotSequenceNumber(msg *SyncMessage) error {
 	if err = ak.Read(a.GetRawMessage()); err != nil {
 		return err
 	}
-	a.Processed = true
-	if err = ak.Do(s.ctx); err != nil {
-		s.config.Services.Log.Errorf("failed to process alert %d; err: %v", a.SequenceNumber, err.Error())
-		a.Processed = false
-	}
 
-	// Save the alert
-	if err = a.Save(s.ctx); err != nil {
+	// Perform the alert action and save the alert
+	if err = models.ApplyAlert(s.ctx, a, ak); err != nil {
 		return err
 	}
 
+	// Publish the alert to the stream subscribers
+	publishAlert(s.ctx, s.config, s.events, a)
+
+	// Queue the webhook (delivered by the webhook delivery cron, so the sync is not held up)
+	if _, err = webhook.Enqueue(s.ctx, s.config, a); err != nil {
+		s.config.Services.Log.Errorf("error queueing webhook delivery for alert %d: %s", a.SequenceNumber, err.Error())
+	}
+
 	// Update the latest sequence
 	s.myLatestSequence = a.SequenceNumber
 	if s.myLatestSequence == s.latestSequence {
@@ -317,3 +329,16 @@ func (s *StreamThread) ProcessWantLatest(ctx context.Context) error {
 	_, err = s.stream.Write(writer.Buf)
 	return err
 }
+
+// publishAlert will publish an accepted alert to the stream subscribers (if any)
+func publishAlert(ctx context.Context, conf *config.Config, broadcaster *events.Broadcaster, alert *models.AlertMessage) {
+	if broadcaster == nil || broadcaster.Subscribers() == 0 {
+		return
+	}
+	event, err := events.NewAlertEvent(ctx, alert)
+	if err != nil {
+		conf.Services.Log.Errorf("failed to create event for alert %d: %s", alert.SequenceNumber, err.Error())
+		return
+	}
+	broadcaster.Publish(event)
+}
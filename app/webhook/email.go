@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+)
+
+// emailSubjectPrefix is the prefix of every email subject
+const emailSubjectPrefix = "[alert-system] "
+
+// emailNotifier sends a plain text email over SMTP
+//
+// The endpoint URL is smtp://host:port, STARTTLS is used when the server supports it.
+type emailNotifier struct{}
+
+// Render will create the email message (the payload template replaces the body, the Date header
+// is added by Send on every attempt)
+func (n *emailNotifier) Render(endpoint *Endpoint, data *TemplateData) ([]byte, error) {
+	body, ok, err := endpoint.execute(data)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		var buf bytes.Buffer
+		buf.WriteString(data.Title() + "\n\n")
+		for _, field := range data.Fields {
+			buf.WriteString(field.Name + ": " + field.Value + "\n")
+		}
+		buf.WriteString(fmt.Sprintf("\nSequence: %d\nHash: %s\nProcessed: %t\nSigned alert: %s\n",
+			data.Sequence, data.Hash, data.Processed, data.RawSigned))
+		body = buf.Bytes()
+	}
+
+	// Headers
+	var msg bytes.Buffer
+	msg.WriteString("From: " + endpoint.Email.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(endpoint.Email.To, ", ") + "\r\n")
+	msg.WriteString("Subject: " + emailSubjectPrefix + data.Title() + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+
+	// Body (SMTP requires CRLF line endings)
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
+
+// Send will send the email to the SMTP server of the endpoint
+func (n *emailNotifier) Send(ctx context.Context, _ *config.Config, endpoint *Endpoint, body []byte) error {
+	u, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return err
+	} else if u.Scheme != "smtp" || len(u.Host) == 0 {
+		return fmt.Errorf("email endpoint URL [%s] must be smtp://host:port", endpoint.URL)
+	}
+
+	// Connect (the context deadline covers the whole conversation)
+	var conn net.Conn
+	if conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", u.Host); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	var c *smtp.Client
+	if c, err = smtp.NewClient(conn, u.Hostname()); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	// Upgrade the connection if supported
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+
+	// Authenticate if configured
+	if len(endpoint.Email.Username) > 0 {
+		if err = c.Auth(smtp.PlainAuth("", endpoint.Email.Username, endpoint.Email.Password, u.Hostname())); err != nil {
+			return err
+		}
+	}
+
+	// Send the message
+	if err = c.Mail(endpoint.Email.From); err != nil {
+		return err
+	}
+	for _, to := range endpoint.Email.To {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write([]byte("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")); err != nil {
+		return err
+	}
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
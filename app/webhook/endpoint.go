@@ -0,0 +1,203 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+)
+
+// Endpoint is a webhook subscriber resolved from the configuration
+type Endpoint struct {
+	Email      config.WebhookEmailConfig
+	Headers    map[string]string
+	Name       string
+	Timeout    time.Duration
+	URL        string
+	alertTypes map[models.AlertType]bool
+	notifier   Notifier
+	secret     string
+	template   *template.Template
+}
+
+// TemplateData is the data available to an endpoint payload template
+type TemplateData struct {
+	AlertType     models.AlertType // Alert type number
+	AlertTypeName string           // Alert type name (e.g. Informational)
+	Fields        []Field          // Decoded alert fields (e.g. Reason, Block hash)
+	Hash          string           // Alert hash
+	Message       string           // Alert message summary (e.g. Informational: <text>)
+	Processed     bool             // True if the alert action succeeded
+	Raw           string           // Raw alert message (hex)
+	RawSigned     string           // Raw signed alert, including the signatures (hex)
+	Sequence      uint32           // Alert sequence number
+	Text          string           // Summary text (same as the default payload)
+}
+
+// Title is the one line title used by the notifiers
+func (d *TemplateData) Title() string {
+	return fmt.Sprintf("Alert #%d: %s", d.Sequence, d.AlertTypeName)
+}
+
+// templateFuncs are the extra functions available to payload templates
+var templateFuncs = template.FuncMap{
+	// json will encode a value as JSON (use it to quote strings inside JSON templates)
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// NewEndpoints will create the endpoints from the configuration
+//
+// The alert_webhook_url (if set) is included as an endpoint for all alert types
+// using the default payload.
+func NewEndpoints(conf *config.Config) ([]*Endpoint, error) {
+	settings := conf.Reloadable()
+	endpoints := make([]*Endpoint, 0, len(settings.WebhookEndpoints)+1)
+	if len(settings.AlertWebhookURL) > 0 {
+		endpoints = append(endpoints, &Endpoint{
+			Name:     config.DefaultWebhookEndpointName,
+			Timeout:  config.DefaultWebhookTimeout,
+			URL:      settings.AlertWebhookURL,
+			notifier: notifiers[config.WebhookFormatJSON],
+		})
+	}
+
+	for _, c := range settings.WebhookEndpoints {
+		endpoint := &Endpoint{
+			Email:   c.Email,
+			Headers: c.Headers,
+			Name:    c.Name,
+			Timeout: c.Timeout,
+			URL:     c.URL,
+			secret:  c.Secret,
+		}
+
+		// Load the notifier for the format
+		format := c.Format
+		if len(format) == 0 {
+			format = config.WebhookFormatJSON
+		}
+		var ok bool
+		if endpoint.notifier, ok = notifiers[format]; !ok {
+			return nil, fmt.Errorf("webhook endpoint [%s]: format [%s] is not supported", c.Name, c.Format)
+		}
+
+		// Load the alert type filter
+		if len(c.AlertTypes) > 0 {
+			endpoint.alertTypes = make(map[models.AlertType]bool, len(c.AlertTypes))
+			for _, name := range c.AlertTypes {
+				alertType, err := parseAlertType(name)
+				if err != nil {
+					return nil, fmt.Errorf("webhook endpoint [%s]: %w", c.Name, err)
+				}
+				endpoint.alertTypes[alertType] = true
+			}
+		}
+
+		// Load the payload template
+		if len(c.PayloadTemplate) > 0 {
+			var err error
+			if endpoint.template, err = template.New(c.Name).Funcs(templateFuncs).Parse(c.PayloadTemplate); err != nil {
+				return nil, fmt.Errorf("webhook endpoint [%s]: %w", c.Name, err)
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+
+// getEndpoint will get a configured endpoint by name
+func getEndpoint(conf *config.Config, name string) (*Endpoint, error) {
+	// Deliveries queued before endpoints had names used the alert_webhook_url
+	if len(name) == 0 {
+		name = config.DefaultWebhookEndpointName
+	}
+
+	endpoints, err := NewEndpoints(conf)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Name == name {
+			return endpoint, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook endpoint [%s] is not configured", name)
+}
+
+// parseAlertType will parse an alert type from its name (e.g. "Invalidate Block",
+// "invalidate_block") or number
+func parseAlertType(name string) (models.AlertType, error) {
+	if n, err := strconv.ParseUint(name, 10, 8); err == nil {
+		alertType := models.AlertType(n)
+		if len(alertType.Name()) > 0 {
+			return alertType, nil
+		}
+	}
+	normalize := strings.NewReplacer(" ", "", "_", "", "-", "")
+	want := normalize.Replace(strings.ToLower(name))
+	for alertType := models.AlertTypeInformational; alertType <= models.AlertTypeSetKeys; alertType++ {
+		if normalize.Replace(strings.ToLower(alertType.Name())) == want {
+			return alertType, nil
+		}
+	}
+	return 0, fmt.Errorf("alert type [%s] is not supported", name)
+}
+
+// Accepts returns true if the endpoint wants alerts of the given type
+func (e *Endpoint) Accepts(alertType models.AlertType) bool {
+	return len(e.alertTypes) == 0 || e.alertTypes[alertType]
+}
+
+// Render will create the notification for the alert
+func (e *Endpoint) Render(alert *models.AlertMessage) ([]byte, error) {
+	data, err := newTemplateData(alert)
+	if err != nil {
+		return nil, err
+	}
+	return e.notifier.Render(e, data)
+}
+
+// execute will run the payload template (if the endpoint has one)
+func (e *Endpoint) execute(data *TemplateData) ([]byte, bool, error) {
+	if e.template == nil {
+		return nil, false, nil
+	}
+	var buf bytes.Buffer
+	if err := e.template.Execute(&buf, data); err != nil {
+		return nil, true, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+// newTemplateData will decode the alert into the data used by the notifiers
+func newTemplateData(alert *models.AlertMessage) (*TemplateData, error) {
+	p, err := NewPayload(alert)
+	if err != nil {
+		return nil, err
+	}
+	am := alert.ProcessAlertMessage()
+	if err = am.Read(alert.GetRawMessage()); err != nil {
+		return nil, err
+	}
+	return &TemplateData{
+		AlertType:     p.AlertType,
+		AlertTypeName: alert.GetAlertType().Name(),
+		Fields:        alertFields(am),
+		Hash:          alert.Hash,
+		Message:       am.MessageString(),
+		Processed:     alert.Processed,
+		Raw:           p.Raw,
+		RawSigned:     p.RawSigned,
+		Sequence:      p.Sequence,
+		Text:          p.Text,
+	}, nil
+}
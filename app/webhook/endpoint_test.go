@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAlert will create an informational alert for testing
+func newTestAlert(message string) *models.AlertMessage {
+	alert := &models.AlertMessage{SequenceNumber: 7}
+	alert.SetAlertType(models.AlertTypeInformational)
+	alert.SetRawMessage(append([]byte{byte(len(message))}, message...))
+	_ = alert.Serialize()
+	return alert
+}
+
+// TestParseAlertType will test the method parseAlertType()
+func TestParseAlertType(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]models.AlertType{
+		"Informational":    models.AlertTypeInformational,
+		"informational":    models.AlertTypeInformational,
+		"Invalidate Block": models.AlertTypeInvalidateBlock,
+		"invalidate_block": models.AlertTypeInvalidateBlock,
+		"confiscate":       models.AlertTypeConfiscateUtxo,
+		"ban-peer":         models.AlertTypeBanPeer,
+		"2":                models.AlertTypeFreezeUtxo,
+	} {
+		alertType, err := parseAlertType(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, alertType, name)
+	}
+
+	for _, name := range []string{"", "0", "99", "unknown"} {
+		_, err := parseAlertType(name)
+		require.Error(t, err, name)
+	}
+}
+
+// TestNewEndpoints will test the method NewEndpoints()
+func TestNewEndpoints(t *testing.T) {
+	t.Parallel()
+
+	t.Run("alert_webhook_url is the default endpoint", func(t *testing.T) {
+		endpoints, err := NewEndpoints(&config.Config{AlertWebhookURL: "https://webhook.url"})
+		require.NoError(t, err)
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, config.DefaultWebhookEndpointName, endpoints[0].Name)
+		assert.Equal(t, config.DefaultWebhookTimeout, endpoints[0].Timeout)
+		assert.True(t, endpoints[0].Accepts(models.AlertTypeSetKeys))
+	})
+
+	t.Run("alert type filter", func(t *testing.T) {
+		endpoints, err := NewEndpoints(&config.Config{Webhook: config.WebhookConfig{Endpoints: []config.WebhookEndpointConfig{
+			{Name: "compliance", URL: "https://compliance.example.com", AlertTypes: []string{"Freeze", "Unfreeze", "Confiscate"}},
+		}}})
+		require.NoError(t, err)
+		require.Len(t, endpoints, 1)
+		assert.True(t, endpoints[0].Accepts(models.AlertTypeFreezeUtxo))
+		assert.True(t, endpoints[0].Accepts(models.AlertTypeConfiscateUtxo))
+		assert.False(t, endpoints[0].Accepts(models.AlertTypeInformational))
+		assert.False(t, endpoints[0].Accepts(models.AlertTypeInvalidateBlock))
+	})
+
+	t.Run("invalid alert type", func(t *testing.T) {
+		_, err := NewEndpoints(&config.Config{Webhook: config.WebhookConfig{Endpoints: []config.WebhookEndpointConfig{
+			{Name: "pagerduty", URL: "https://events.pagerduty.com", AlertTypes: []string{"Invalidate Blocks"}},
+		}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "pagerduty")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := NewEndpoints(&config.Config{Webhook: config.WebhookConfig{Endpoints: []config.WebhookEndpointConfig{
+			{Name: "slack", URL: "https://hooks.slack.com", PayloadTemplate: `{"text": {{ .Message }`},
+		}}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "slack")
+	})
+}
+
+// TestEndpoint_Render will test the method Render()
+func TestEndpoint_Render(t *testing.T) {
+	t.Parallel()
+
+	alert := newTestAlert(`say "hello"`)
+
+	t.Run("default payload", func(t *testing.T) {
+		endpoints, err := NewEndpoints(&config.Config{AlertWebhookURL: "https://webhook.url"})
+		require.NoError(t, err)
+
+		var body []byte
+		body, err = endpoints[0].Render(alert)
+		require.NoError(t, err)
+
+		var p Payload
+		require.NoError(t, json.Unmarshal(body, &p))
+		assert.Equal(t, models.AlertTypeInformational, p.AlertType)
+		assert.Equal(t, uint32(7), p.Sequence)
+		assert.Equal(t, alert.Raw, p.RawSigned)
+	})
+
+	t.Run("payload template", func(t *testing.T) {
+		endpoints, err := NewEndpoints(&config.Config{Webhook: config.WebhookConfig{Endpoints: []config.WebhookEndpointConfig{
+			{Name: "slack", URL: "https://hooks.slack.com", PayloadTemplate: `{"text": {{ printf "[%d] %s: %s" .Sequence .AlertTypeName .Message | json }}}`},
+		}}})
+		require.NoError(t, err)
+
+		var body []byte
+		body, err = endpoints[0].Render(alert)
+		require.NoError(t, err)
+
+		var slack map[string]string
+		require.NoError(t, json.Unmarshal(body, &slack))
+		assert.Equal(t, `[7] Informational: Informational: say "hello"`, slack["text"])
+	})
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	bnmodels "github.com/bsv-blockchain/go-bn/models"
+)
+
+// Notifier is a notification backend, selected by the endpoint format
+type Notifier interface {
+	// Render will create the notification for an alert (this is what is stored in the outbox)
+	Render(endpoint *Endpoint, data *TemplateData) ([]byte, error)
+
+	// Send will deliver a rendered notification to the endpoint
+	Send(ctx context.Context, conf *config.Config, endpoint *Endpoint, body []byte) error
+}
+
+// notifiers are the notification backends by endpoint format
+var notifiers = map[string]Notifier{
+	config.WebhookFormatEmail: &emailNotifier{},
+	config.WebhookFormatJSON:  &jsonNotifier{},
+	config.WebhookFormatSlack: &slackNotifier{},
+	config.WebhookFormatTeams: &teamsNotifier{},
+}
+
+// Field is a decoded alert field shown by the notifiers
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// alertFields will decode the fields of an alert message for display
+func alertFields(am models.AlertMessageInterface) []Field {
+	switch a := am.(type) {
+	case *models.AlertMessageInformational:
+		return []Field{{Name: "Message", Value: string(a.Message)}}
+	case *models.AlertMessageInvalidateBlock:
+		fields := make([]Field, 0, 2)
+		if a.BlockHash != nil {
+			fields = append(fields, Field{Name: "Block hash", Value: a.BlockHash.String()})
+		}
+		return append(fields, Field{Name: "Reason", Value: string(a.Reason)})
+	case *models.AlertMessageBanPeer:
+		return []Field{{Name: "Peer", Value: string(a.Peer)}, {Name: "Reason", Value: string(a.Reason)}}
+	case *models.AlertMessageUnbanPeer:
+		return []Field{{Name: "Peer", Value: string(a.Peer)}, {Name: "Reason", Value: string(a.Reason)}}
+	case *models.AlertMessageFreezeUtxo:
+		return fundFields(a.Funds)
+	case *models.AlertMessageUnfreezeUtxo:
+		return fundFields(a.Funds)
+	case *models.AlertMessageConfiscateTransaction:
+		fields := make([]Field, 0, 2*len(a.Transactions))
+		for _, tx := range a.Transactions {
+			fields = append(fields,
+				Field{Name: "Enforce at height", Value: strconv.FormatInt(tx.ConfiscationTransaction.EnforceAtHeight, 10)},
+				Field{Name: "Transaction", Value: tx.ConfiscationTransaction.Hex},
+			)
+		}
+		return fields
+	case *models.AlertMessageSetKeys:
+		fields := make([]Field, 0, len(a.Keys))
+		for i, key := range a.Keys {
+			fields = append(fields, Field{Name: fmt.Sprintf("Key %d", i+1), Value: hex.EncodeToString(key[:])})
+		}
+		return fields
+	}
+	return []Field{{Name: "Message", Value: am.MessageString()}}
+}
+
+// fundFields will create the fields for a list of frozen or unfrozen funds
+func fundFields(funds []bnmodels.Fund) []Field {
+	fields := make([]Field, 0, len(funds))
+	for i, fund := range funds {
+		value := fmt.Sprintf("%s:%d", fund.TxOut.TxId, fund.TxOut.Vout)
+		for _, enforce := range fund.EnforceAtHeight {
+			value += fmt.Sprintf(" (heights %d-%d)", enforce.Start, enforce.Stop)
+		}
+		if fund.PolicyExpiresWithConsensus {
+			value += " (policy expires with consensus)"
+		}
+		fields = append(fields, Field{Name: fmt.Sprintf("Fund %d", i+1), Value: value})
+	}
+	return fields
+}
+
+// httpSender sends a rendered notification with a POST request to the endpoint URL
+type httpSender struct{}
+
+// Send will POST the body to the endpoint URL, signing it if the endpoint has a secret
+func (httpSender) Send(ctx context.Context, conf *config.Config, endpoint *Endpoint, body []byte) error {
+	return Post(ctx, conf.Services.HTTPClient, endpoint.URL, endpoint.signedHeaders(time.Now(), body), body)
+}
+
+// jsonNotifier sends the default JSON payload
+type jsonNotifier struct {
+	httpSender
+}
+
+// Render will create the default JSON payload (or run the payload template)
+func (n *jsonNotifier) Render(endpoint *Endpoint, data *TemplateData) ([]byte, error) {
+	if body, ok, err := endpoint.execute(data); ok {
+		return body, err
+	}
+	return json.Marshal(&Payload{
+		AlertType: data.AlertType,
+		Raw:       data.Raw,
+		RawSigned: data.RawSigned,
+		Sequence:  data.Sequence,
+		Text:      data.Text,
+	})
+}
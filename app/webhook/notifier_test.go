@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEndpoint will create a single endpoint for testing
+func newTestEndpoint(t *testing.T, c config.WebhookEndpointConfig) *Endpoint {
+	c.Name = "test"
+	c.Timeout = time.Second
+	endpoints, err := NewEndpoints(&config.Config{Webhook: config.WebhookConfig{Endpoints: []config.WebhookEndpointConfig{c}}})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	return endpoints[0]
+}
+
+// TestAlertFields will test the method alertFields()
+func TestAlertFields(t *testing.T) {
+	t.Parallel()
+
+	am := newTestAlert("hello").ProcessAlertMessage()
+	require.NoError(t, am.Read(append([]byte{5}, "hello"...)))
+	assert.Equal(t, []Field{{Name: "Message", Value: "hello"}}, alertFields(am))
+
+	banPeer := &models.AlertMessageBanPeer{Peer: []byte("10.0.0.1"), Reason: []byte("spam")}
+	assert.Equal(t, []Field{{Name: "Peer", Value: "10.0.0.1"}, {Name: "Reason", Value: "spam"}}, alertFields(banPeer))
+}
+
+// TestSlackNotifier will test the Slack format
+func TestSlackNotifier(t *testing.T) {
+	t.Parallel()
+
+	endpoint := newTestEndpoint(t, config.WebhookEndpointConfig{Format: config.WebhookFormatSlack, URL: "https://hooks.slack.com/services/test"})
+	body, err := endpoint.Render(newTestAlert("hello"))
+	require.NoError(t, err)
+
+	var msg slackMessage
+	require.NoError(t, json.Unmarshal(body, &msg))
+	assert.Equal(t, "Alert #7: Informational", msg.Text)
+	require.Len(t, msg.Blocks, 3)
+	assert.Equal(t, "header", msg.Blocks[0].Type)
+	assert.Equal(t, "section", msg.Blocks[1].Type)
+	require.Len(t, msg.Blocks[1].Fields, 1)
+	assert.Equal(t, "*Message*\nhello", msg.Blocks[1].Fields[0].Text)
+	assert.Equal(t, "context", msg.Blocks[2].Type)
+
+	// Sent as a JSON POST
+	httpClient := &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "https://hooks.slack.com/services/test", req.URL.String())
+			assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+	}
+	conf := &config.Config{Services: config.Services{HTTPClient: httpClient}}
+	require.NoError(t, endpoint.notifier.Send(context.Background(), conf, endpoint, body))
+}
+
+// TestTeamsNotifier will test the Teams format
+func TestTeamsNotifier(t *testing.T) {
+	t.Parallel()
+
+	endpoint := newTestEndpoint(t, config.WebhookEndpointConfig{Format: config.WebhookFormatTeams, URL: "https://example.webhook.office.com/test"})
+	body, err := endpoint.Render(newTestAlert("hello"))
+	require.NoError(t, err)
+
+	var msg teamsMessage
+	require.NoError(t, json.Unmarshal(body, &msg))
+	assert.Equal(t, "message", msg.Type)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, teamsCardContentType, msg.Attachments[0].ContentType)
+	card := msg.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card.Type)
+	require.Len(t, card.Body, 3)
+	assert.Equal(t, "Alert #7: Informational", card.Body[0].Text)
+	assert.Equal(t, []teamsFact{{Title: "Message", Value: "hello"}}, card.Body[1].Facts)
+}
+
+// smtpStandIn is a minimal local SMTP server that records the messages it receives
+type smtpStandIn struct {
+	listener net.Listener
+	messages chan string
+	rcpts    chan []string
+}
+
+// newSMTPStandIn will start a local SMTP stand-in
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: l, messages: make(chan string, 1), rcpts: make(chan []string, 1)}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+// serve will handle a single SMTP conversation at a time
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP stand-in")
+		var rcpts []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM"):
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO"):
+				rcpts = append(rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 end data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				s.rcpts <- rcpts
+				s.messages <- data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				_ = conn.Close()
+			default:
+				reply("502 not implemented")
+			}
+		}
+		_ = conn.Close()
+	}
+}
+
+// TestEmailNotifier will test the email format against a local SMTP stand-in
+func TestEmailNotifier(t *testing.T) {
+	t.Parallel()
+
+	server := newSMTPStandIn(t)
+	endpoint := newTestEndpoint(t, config.WebhookEndpointConfig{
+		Email: config.WebhookEmailConfig{
+			From: "alerts@example.com",
+			To:   []string{"soc@example.com", "compliance@example.com"},
+		},
+		Format: config.WebhookFormatEmail,
+		URL:    "smtp://" + server.listener.Addr().String(),
+	})
+
+	body, err := endpoint.Render(newTestAlert("hello"))
+	require.NoError(t, err)
+	msg := string(body)
+	assert.Contains(t, msg, "From: alerts@example.com\r\n")
+	assert.Contains(t, msg, "To: soc@example.com, compliance@example.com\r\n")
+	assert.Contains(t, msg, "Subject: [alert-system] Alert #7: Informational\r\n")
+	assert.Contains(t, msg, "\r\nMessage: hello\r\n")
+	assert.NotContains(t, msg, "Date: ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, endpoint.notifier.Send(ctx, &config.Config{}, endpoint, body))
+	assert.Equal(t, []string{"soc@example.com", "compliance@example.com"}, <-server.rcpts)
+	sent := <-server.messages
+	assert.True(t, strings.HasPrefix(sent, "Date: "))
+	assert.Contains(t, sent, "Message: hello\r\n")
+
+	t.Run("invalid url", func(t *testing.T) {
+		bad := *endpoint
+		bad.URL = "mail.example.com:25"
+		require.Error(t, bad.notifier.Send(ctx, &config.Config{}, &bad, body))
+	})
+}
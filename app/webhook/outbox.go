@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/models/model"
+)
+
+// ErrDeliveryNotFound is returned when a webhook delivery does not exist
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// claimMargin is added to the endpoint timeout when a delivery is claimed for an attempt (see EnqueueAndDeliver)
+const claimMargin = time.Minute
+
+// NextBackoff returns the delay before the next attempt after the given number of failed attempts
+//
+// The delay doubles with every attempt, starting at initial and capped at max.
+func NextBackoff(attempts uint32, initial, max time.Duration) time.Duration {
+	if attempts <= 1 {
+		return initial
+	}
+	backoff := initial
+	for i := uint32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= max || backoff <= 0 {
+			return max
+		}
+	}
+	return backoff
+}
+
+// Enqueue will add the alert to the webhook outbox of every endpoint that accepts its type
+//
+// The deliveries are persisted before anything is sent, so they survive failed attempts
+// and restarts. They are due immediately and will be picked up by ProcessDeliveries,
+// callers that want lower latency use EnqueueAndDeliver.
+//
+// An alert that already has deliveries (e.g. synced again from a peer) is not queued again. An endpoint
+// that fails does not stop the other endpoints, the errors are returned together.
+func Enqueue(ctx context.Context, conf *config.Config, alert *models.AlertMessage) ([]*models.WebhookDelivery, error) {
+	return enqueue(ctx, conf, alert, false)
+}
+
+// EnqueueAndDeliver will add the alert to the webhook outbox (see Enqueue) and make the first attempt of
+// every delivery right away
+//
+// The deliveries are claimed for the first attempt: they are not due until the attempt timed out, so
+// ProcessDeliveries does not attempt them at the same time. A failed attempt is retried by ProcessDeliveries.
+func EnqueueAndDeliver(ctx context.Context, conf *config.Config, alert *models.AlertMessage) ([]*models.WebhookDelivery, error) {
+	deliveries, err := enqueue(ctx, conf, alert, true)
+	for _, delivery := range deliveries {
+		if deliverErr := Deliver(ctx, conf, delivery); deliverErr != nil {
+			conf.Services.Log.Errorf(
+				"failed to deliver webhook %d for alert %d to [%s]: %s",
+				delivery.ID, delivery.SequenceNumber, delivery.Endpoint, deliverErr.Error(),
+			)
+		}
+	}
+	return deliveries, err
+}
+
+// enqueue will add the alert to the webhook outbox, the deliveries are due immediately unless claimed
+// (due after the timeout of the endpoint, for an attempt by the caller)
+func enqueue(ctx context.Context, conf *config.Config, alert *models.AlertMessage,
+	claim bool) ([]*models.WebhookDelivery, error) {
+	existing, err := models.GetWebhookDeliveryByAlertHash(ctx, alert.Hash, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, nil
+	}
+
+	var endpoints []*Endpoint
+	if endpoints, err = NewEndpoints(conf); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(endpoints))
+	var errs []error
+	for _, endpoint := range endpoints {
+		if !endpoint.Accepts(alert.GetAlertType()) {
+			continue
+		}
+
+		// Create the payload
+		var payload []byte
+		if payload, err = endpoint.Render(alert); err != nil {
+			errs = append(errs, fmt.Errorf("webhook endpoint [%s]: %w", endpoint.Name, err))
+			continue
+		}
+
+		// Save the delivery into the outbox
+		delivery := models.NewWebhookDelivery(model.WithAllDependencies(conf), model.New())
+		delivery.AlertHash = alert.Hash
+		delivery.SequenceNumber = alert.SequenceNumber
+		delivery.Endpoint = endpoint.Name
+		delivery.URL = endpoint.URL
+		delivery.Payload = string(payload)
+		delivery.NextAttemptAt = time.Now().UTC()
+		if claim {
+			delivery.NextAttemptAt = delivery.NextAttemptAt.Add(endpoint.Timeout + claimMargin)
+		}
+		if err = delivery.Save(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("webhook endpoint [%s]: %w", endpoint.Name, err))
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, errors.Join(errs...)
+}
+
+// Deliver will make a single delivery attempt and record the outcome
+//
+// The URL, headers, secret and timeout of the endpoint are taken from the current configuration
+// (so a fixed endpoint can be replayed), the payload is signed again on every attempt.
+// After the configured maximum number of attempts the delivery is moved to the dead state.
+func Deliver(ctx context.Context, conf *config.Config, delivery *models.WebhookDelivery) error {
+	delivery.Attempts++
+	endpoint, err := getEndpoint(conf, delivery.Endpoint)
+	if err == nil {
+		postCtx, cancel := context.WithTimeout(ctx, endpoint.Timeout)
+		err = endpoint.notifier.Send(postCtx, conf, endpoint, []byte(delivery.Payload))
+		cancel()
+	}
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryStatusDelivered
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= conf.Webhook.MaxAttempts {
+			delivery.Status = models.WebhookDeliveryStatusDead
+		} else {
+			delivery.NextAttemptAt = time.Now().UTC().Add(
+				NextBackoff(delivery.Attempts, conf.Webhook.InitialBackoff, conf.Webhook.MaxBackoff),
+			)
+		}
+	}
+
+	// Save the outcome
+	if saveErr := delivery.Save(ctx); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// ProcessDeliveries will attempt all pending deliveries that are due, returning the number delivered
+func ProcessDeliveries(ctx context.Context, conf *config.Config) (int, error) {
+	deliveries, err := models.GetDueWebhookDeliveries(ctx, time.Now().UTC(), model.WithAllDependencies(conf))
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		delivery.SetOptions(model.WithAllDependencies(conf))
+		if err = Deliver(ctx, conf, delivery); err != nil {
+			conf.Services.Log.Errorf(
+				"failed to deliver webhook %d for alert %d (attempt %d): %s",
+				delivery.ID, delivery.SequenceNumber, delivery.Attempts, err.Error(),
+			)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// Replay will reset a delivery and attempt it again
+//
+// This is used to recover dead deliveries once the receiving endpoint is fixed.
+// The outcome of the attempt is recorded on the returned delivery.
+func Replay(ctx context.Context, conf *config.Config, id uint64) (*models.WebhookDelivery, error) {
+	delivery, err := models.GetWebhookDeliveryByID(ctx, id, model.WithAllDependencies(conf))
+	if err != nil {
+		return nil, err
+	} else if delivery == nil {
+		return nil, ErrDeliveryNotFound
+	}
+
+	// Reset the delivery
+	delivery.Status = models.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+
+	// The error is recorded on the delivery itself
+	if err = Deliver(ctx, conf, delivery); err != nil {
+		conf.Services.Log.Errorf("failed to replay webhook delivery %d: %s", delivery.ID, err.Error())
+	}
+	return delivery, nil
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNextBackoff will test the method NextBackoff()
+func TestNextBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("doubles every attempt", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, NextBackoff(0, 30*time.Second, time.Hour))
+		assert.Equal(t, 30*time.Second, NextBackoff(1, 30*time.Second, time.Hour))
+		assert.Equal(t, time.Minute, NextBackoff(2, 30*time.Second, time.Hour))
+		assert.Equal(t, 2*time.Minute, NextBackoff(3, 30*time.Second, time.Hour))
+		assert.Equal(t, 4*time.Minute, NextBackoff(4, 30*time.Second, time.Hour))
+	})
+
+	t.Run("capped at max", func(t *testing.T) {
+		assert.Equal(t, time.Hour, NextBackoff(10, 30*time.Second, time.Hour))
+		assert.Equal(t, time.Hour, NextBackoff(1000, 30*time.Second, time.Hour))
+	})
+}
+
+// TestPost will test the method Post()
+func TestPost(t *testing.T) {
+	t.Parallel()
+
+	const url = "https://webhook.example.com/alerts"
+
+	t.Run("2xx is delivered", func(t *testing.T) {
+		for _, code := range []int{http.StatusOK, http.StatusAccepted, http.StatusNoContent} {
+			httpClient := &MockHTTPClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPost, req.Method)
+					assert.Equal(t, url, req.URL.String())
+					assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+					return &http.Response{StatusCode: code, Body: http.NoBody}, nil
+				},
+			}
+			require.NoError(t, Post(context.Background(), httpClient, url, nil, []byte(`{}`)))
+		}
+	})
+
+	t.Run("non-2xx is an error", func(t *testing.T) {
+		for _, code := range []int{http.StatusMovedPermanently, http.StatusBadRequest, http.StatusInternalServerError} {
+			httpClient := &MockHTTPClient{
+				DoFunc: func(_ *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: code, Body: http.NoBody}, nil
+				},
+			}
+			require.Error(t, Post(context.Background(), httpClient, url, nil, []byte(`{}`)))
+		}
+	})
+
+	t.Run("custom headers", func(t *testing.T) {
+		httpClient := &MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "Token 12345", req.Header.Get("Authorization"))
+				assert.Equal(t, "text/plain", req.Header.Get("Content-Type"))
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			},
+		}
+		require.NoError(t, Post(context.Background(), httpClient, url, map[string]string{
+			"authorization": "Token 12345",
+			"content-type":  "text/plain",
+		}, []byte(`hello`)))
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		require.Error(t, Post(context.Background(), &MockHTTPClient{}, url, nil, []byte(`{}`)))
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		require.Error(t, Post(context.Background(), nil, "", nil, []byte(`{}`)))
+		require.Error(t, Post(context.Background(), nil, "ftp://example.com", nil, []byte(`{}`)))
+	})
+}
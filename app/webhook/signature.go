@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature headers sent with every request to an endpoint that has a secret
+const (
+	HeaderSignature = "X-Alert-System-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+	HeaderTimestamp = "X-Alert-System-Timestamp" // Unix time (seconds) the request was signed
+	signaturePrefix = "sha256="
+)
+
+// Signature errors
+var (
+	ErrSignatureInvalid = errors.New("webhook signature is invalid")
+	ErrSignatureMissing = errors.New("webhook signature or timestamp header is missing")
+	ErrSignatureExpired = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// Sign will create the signature of a request body
+//
+// The timestamp is part of the signed content, so a captured request cannot be
+// replayed later with a new timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify will check the signature headers of a received request body
+//
+// Receivers should reject requests with a timestamp further than tolerance from now
+// and remember recently seen signatures if they need strict replay protection.
+func Verify(secret string, header http.Header, body []byte, now time.Time, tolerance time.Duration) error {
+	signature := header.Get(HeaderSignature)
+	timestampStr := header.Get(HeaderTimestamp)
+	if len(signature) == 0 || len(timestampStr) == 0 {
+		return ErrSignatureMissing
+	}
+
+	// Check the timestamp
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	// Check the signature
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// signedHeaders will add the signature headers to the endpoint headers (if the endpoint has a secret)
+func (e *Endpoint) signedHeaders(now time.Time, body []byte) map[string]string {
+	if len(e.secret) == 0 {
+		return e.Headers
+	}
+	headers := make(map[string]string, len(e.Headers)+2)
+	for key, value := range e.Headers {
+		headers[key] = value
+	}
+	timestamp := now.Unix()
+	headers[HeaderTimestamp] = strconv.FormatInt(timestamp, 10)
+	headers[HeaderSignature] = Sign(e.secret, timestamp, body)
+	return headers
+}
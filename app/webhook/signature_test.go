@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+// TestSign will test the method Sign()
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"sequence":1}`)
+	signature := Sign(testSecret, 1700000000, body)
+	assert.Equal(t, signature, Sign(testSecret, 1700000000, body))
+	assert.Len(t, signature, len(signaturePrefix)+64)
+	assert.NotEqual(t, signature, Sign(testSecret, 1700000001, body))
+	assert.NotEqual(t, signature, Sign("other-secret", 1700000000, body))
+	assert.NotEqual(t, signature, Sign(testSecret, 1700000000, []byte(`{"sequence":2}`)))
+}
+
+// TestVerify will test the method Verify()
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"sequence":1}`)
+	headers := func(endpoint *Endpoint, signedAt time.Time) http.Header {
+		h := http.Header{}
+		for key, value := range endpoint.signedHeaders(signedAt, body) {
+			h.Set(key, value)
+		}
+		return h
+	}
+	endpoint := &Endpoint{Headers: map[string]string{"Authorization": "Token 12345"}, secret: testSecret}
+
+	t.Run("valid", func(t *testing.T) {
+		h := headers(endpoint, now)
+		require.NoError(t, Verify(testSecret, h, body, now, 5*time.Minute))
+		assert.Equal(t, "Token 12345", h.Get("Authorization"))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		require.ErrorIs(t, Verify("other-secret", headers(endpoint, now), body, now, 5*time.Minute), ErrSignatureInvalid)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		require.ErrorIs(t, Verify(testSecret, headers(endpoint, now), []byte(`{"sequence":2}`), now, 5*time.Minute), ErrSignatureInvalid)
+	})
+
+	t.Run("replayed outside the tolerance", func(t *testing.T) {
+		require.ErrorIs(t, Verify(testSecret, headers(endpoint, now.Add(-10*time.Minute)), body, now, 5*time.Minute), ErrSignatureExpired)
+		require.ErrorIs(t, Verify(testSecret, headers(endpoint, now.Add(10*time.Minute)), body, now, 5*time.Minute), ErrSignatureExpired)
+	})
+
+	t.Run("new timestamp on a captured signature", func(t *testing.T) {
+		h := headers(endpoint, now.Add(-10*time.Minute))
+		h.Set(HeaderTimestamp, "1700000000")
+		require.ErrorIs(t, Verify(testSecret, h, body, now, 5*time.Minute), ErrSignatureInvalid)
+	})
+
+	t.Run("unsigned endpoint", func(t *testing.T) {
+		unsigned := &Endpoint{Headers: map[string]string{"Authorization": "Token 12345"}}
+		require.ErrorIs(t, Verify(testSecret, headers(unsigned, now), body, now, 5*time.Minute), ErrSignatureMissing)
+	})
+}
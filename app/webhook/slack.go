@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Slack block kit limits
+const (
+	slackMaxFields    = 10   // Fields in a single section block
+	slackMaxFieldText = 2000 // Characters in a single field
+)
+
+// slackNotifier sends a Slack incoming webhook message with blocks
+type slackNotifier struct {
+	httpSender
+}
+
+// slackText is a Slack text object
+type slackText struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// slackBlock is a Slack layout block
+type slackBlock struct {
+	Elements []slackText `json:"elements,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Text     *slackText  `json:"text,omitempty"`
+	Type     string      `json:"type"`
+}
+
+// slackMessage is the body of a Slack incoming webhook request
+type slackMessage struct {
+	Blocks []slackBlock `json:"blocks"`
+	Text   string       `json:"text"` // Fallback for notifications
+}
+
+// Render will create the Slack message (or run the payload template)
+func (n *slackNotifier) Render(endpoint *Endpoint, data *TemplateData) ([]byte, error) {
+	if body, ok, err := endpoint.execute(data); ok {
+		return body, err
+	}
+
+	blocks := []slackBlock{{
+		Text: &slackText{Text: data.Title(), Type: "plain_text"},
+		Type: "header",
+	}}
+
+	// Decoded fields, in sections of at most 10 fields
+	for i := 0; i < len(data.Fields); i += slackMaxFields {
+		section := slackBlock{Type: "section"}
+		for _, field := range data.Fields[i:min(i+slackMaxFields, len(data.Fields))] {
+			section.Fields = append(section.Fields, slackText{
+				Text: truncate(fmt.Sprintf("*%s*\n%s", field.Name, field.Value), slackMaxFieldText),
+				Type: "mrkdwn",
+			})
+		}
+		blocks = append(blocks, section)
+	}
+
+	blocks = append(blocks, slackBlock{
+		Elements: []slackText{{
+			Text: fmt.Sprintf("Sequence `%d` | Hash `%s` | Processed `%t`", data.Sequence, data.Hash, data.Processed),
+			Type: "mrkdwn",
+		}},
+		Type: "context",
+	})
+
+	return json.Marshal(&slackMessage{Blocks: blocks, Text: data.Title()})
+}
+
+// truncate will shorten a string to at most max characters
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
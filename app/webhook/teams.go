@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Adaptive card content types
+const (
+	teamsCardContentType = "application/vnd.microsoft.card.adaptive"
+	teamsCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	teamsCardVersion     = "1.4"
+)
+
+// teamsNotifier sends a Microsoft Teams message with an adaptive card
+type teamsNotifier struct {
+	httpSender
+}
+
+// teamsFact is a row of an adaptive card fact set
+type teamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// teamsElement is an adaptive card element
+type teamsElement struct {
+	Facts    []teamsFact `json:"facts,omitempty"`
+	IsSubtle bool        `json:"isSubtle,omitempty"`
+	Size     string      `json:"size,omitempty"`
+	Text     string      `json:"text,omitempty"`
+	Type     string      `json:"type"`
+	Weight   string      `json:"weight,omitempty"`
+	Wrap     bool        `json:"wrap,omitempty"`
+}
+
+// teamsCard is an adaptive card
+type teamsCard struct {
+	Body    []teamsElement `json:"body"`
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+}
+
+// teamsAttachment is a message attachment
+type teamsAttachment struct {
+	Content     teamsCard `json:"content"`
+	ContentType string    `json:"contentType"`
+}
+
+// teamsMessage is the body of a Teams incoming webhook request
+type teamsMessage struct {
+	Attachments []teamsAttachment `json:"attachments"`
+	Type        string            `json:"type"`
+}
+
+// Render will create the Teams message (or run the payload template)
+func (n *teamsNotifier) Render(endpoint *Endpoint, data *TemplateData) ([]byte, error) {
+	if body, ok, err := endpoint.execute(data); ok {
+		return body, err
+	}
+
+	facts := make([]teamsFact, 0, len(data.Fields))
+	for _, field := range data.Fields {
+		facts = append(facts, teamsFact{Title: field.Name, Value: field.Value})
+	}
+
+	return json.Marshal(&teamsMessage{
+		Attachments: []teamsAttachment{{
+			Content: teamsCard{
+				Body: []teamsElement{
+					{Size: "Medium", Text: data.Title(), Type: "TextBlock", Weight: "Bolder", Wrap: true},
+					{Facts: facts, Type: "FactSet"},
+					{
+						IsSubtle: true,
+						Text:     fmt.Sprintf("Sequence %d | Hash %s | Processed %t", data.Sequence, data.Hash, data.Processed),
+						Type:     "TextBlock",
+						Wrap:     true,
+					},
+				},
+				Schema:  teamsCardSchema,
+				Type:    "AdaptiveCard",
+				Version: teamsCardVersion,
+			},
+			ContentType: teamsCardContentType,
+		}},
+		Type: "message",
+	})
+}
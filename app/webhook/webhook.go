@@ -18,34 +18,42 @@ import (
 type Payload struct {
 	AlertType models.AlertType `json:"alert_type"`
 	Raw       string           `json:"raw"`
+	RawSigned string           `json:"raw_signed"` // The full alert with signatures (to verify against the public keys)
 	Sequence  uint32           `json:"sequence"`
 	Text      string           `json:"text"`
 }
 
-// PostAlert sends an alert to a webhook URL using the provided http client
-func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string, alert *models.AlertMessage) error {
-	var err error
-	// Validate the URL length
-	if len(url) == 0 {
-		return fmt.Errorf("webhook URL is not configured")
+// NewPayload creates the webhook payload for an alert
+func NewPayload(alert *models.AlertMessage) (*Payload, error) {
+	am := alert.ProcessAlertMessage()
+	if am == nil {
+		return nil, fmt.Errorf("alert type [%d] is not supported", alert.GetAlertType())
 	}
-
-	// Validate the URL prefix
-	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		return fmt.Errorf("webhook URL [%s] is does not have a valid prefix", url)
+	if err := am.Read(alert.GetRawMessage()); err != nil {
+		return nil, err
 	}
 
-	am := alert.ProcessAlertMessage()
-	err = am.Read(alert.GetRawMessage())
-	if err != nil {
-		return err
-	}
 	// Create the payload
-	p := Payload{
+	return &Payload{
 		AlertType: alert.GetAlertType(),
 		Sequence:  alert.SequenceNumber,
 		Raw:       hex.EncodeToString(alert.GetRawMessage()),
+		RawSigned: alert.Raw,
 		Text:      fmt.Sprintf("Sequence [`%d`], alert type [`%s`], message: [`%s`], processed: [`%v`]", alert.SequenceNumber, alert.GetAlertType().Name(), am.MessageString(), alert.Processed),
+	}, nil
+}
+
+// PostAlert sends an alert to a webhook URL using the provided http client
+func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string, alert *models.AlertMessage) error {
+	// Validate the URL
+	if err := validateURL(url); err != nil {
+		return err
+	}
+
+	// Create the payload
+	p, err := NewPayload(alert)
+	if err != nil {
+		return err
 	}
 
 	// Marshal the payload
@@ -54,6 +62,33 @@ func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string,
 		return err
 	}
 
+	return Post(ctx, httpClient, url, nil, payload)
+}
+
+// validateURL will validate the webhook URL
+func validateURL(url string) error {
+	// Validate the URL length
+	if len(url) == 0 {
+		return fmt.Errorf("webhook URL is not configured")
+	}
+
+	// Validate the URL prefix
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return fmt.Errorf("webhook URL [%s] is does not have a valid prefix", url)
+	}
+	return nil
+}
+
+// Post sends a payload to a webhook URL using the provided http client
+//
+// The Content-Type defaults to JSON and can be replaced with the extra headers.
+func Post(ctx context.Context, httpClient config.HTTPInterface, url string, headers map[string]string, payload []byte) error {
+	// Validate the URL
+	err := validateURL(url)
+	if err != nil {
+		return err
+	}
+
 	// Create the http request
 	var req *http.Request
 	if req, err = http.NewRequestWithContext(
@@ -62,6 +97,9 @@ func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string,
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	// Fire the http request
 	var res *http.Response
@@ -74,8 +112,8 @@ func PostAlert(ctx context.Context, httpClient config.HTTPInterface, url string,
 		}
 	}()
 
-	// Validate the response
-	if res != nil && res.StatusCode != http.StatusOK {
+	// Validate the response (any 2xx is accepted)
+	if res != nil && (res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices) {
 		return fmt.Errorf("unexpected status code [%d] sending payload to webhook", res.StatusCode)
 	}
 	return nil
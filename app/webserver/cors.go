@@ -0,0 +1,125 @@
+package webserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+)
+
+// CORS header names
+const (
+	headerAllowCredentials = "Access-Control-Allow-Credentials"
+	headerAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAllowMethods     = "Access-Control-Allow-Methods"
+	headerAllowOrigin      = "Access-Control-Allow-Origin"
+	headerMaxAge           = "Access-Control-Max-Age"
+	headerOrigin           = "Origin"
+	headerRequestMethod    = "Access-Control-Request-Method"
+	headerVary             = "Vary"
+)
+
+// corsPolicy applies the configured CORS policy to incoming requests
+type corsPolicy struct {
+	allowAll         bool
+	allowCredentials bool
+	allowedHeaders   string
+	allowedMethods   map[string]bool
+	allowedOrigins   map[string]bool
+	methods          string
+	maxAge           string
+}
+
+// newCORSPolicy will create a policy from the web server configuration
+func newCORSPolicy(conf config.CORSConfig) *corsPolicy {
+	p := &corsPolicy{
+		allowCredentials: conf.AllowCredentials,
+		allowedHeaders:   strings.Join(conf.AllowedHeaders, ", "),
+		allowedMethods:   make(map[string]bool, len(conf.AllowedMethods)),
+		allowedOrigins:   make(map[string]bool, len(conf.AllowedOrigins)),
+	}
+
+	for _, origin := range conf.AllowedOrigins {
+		if origin == wildcard {
+			p.allowAll = true
+			continue
+		}
+		p.allowedOrigins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	methods := make([]string, 0, len(conf.AllowedMethods))
+	for _, method := range conf.AllowedMethods {
+		method = strings.ToUpper(method)
+		p.allowedMethods[method] = true
+		methods = append(methods, method)
+	}
+	p.methods = strings.Join(methods, ", ")
+
+	if conf.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(conf.MaxAge.Seconds()))
+	}
+
+	return p
+}
+
+// isOriginAllowed returns true if the origin is allowed by the policy
+func (p *corsPolicy) isOriginAllowed(origin string) bool {
+	return p.allowAll || p.allowedOrigins[strings.ToLower(origin)]
+}
+
+// setOriginHeaders will set the headers that are shared by preflight and actual requests
+func (p *corsPolicy) setOriginHeaders(w http.ResponseWriter, origin string) {
+	// A wildcard can never be combined with credentials, echo the origin instead
+	if p.allowAll && !p.allowCredentials {
+		w.Header().Set(headerAllowOrigin, wildcard)
+	} else {
+		w.Header().Set(headerAllowOrigin, origin)
+	}
+	if p.allowCredentials {
+		w.Header().Set(headerAllowCredentials, "true")
+	}
+}
+
+// Handler wraps the next handler with the CORS policy
+//
+// Preflight requests are answered directly. Requests from origins that are not
+// allowed get no CORS headers, so browsers will refuse to share the response.
+func (p *corsPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get(headerOrigin)
+
+		// Not a cross-origin request
+		if len(origin) == 0 {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		// The response depends on the origin (for caches)
+		w.Header().Add(headerVary, headerOrigin)
+
+		// Preflight request
+		if req.Method == http.MethodOptions && len(req.Header.Get(headerRequestMethod)) > 0 {
+			if !p.isOriginAllowed(origin) || !p.allowedMethods[strings.ToUpper(req.Header.Get(headerRequestMethod))] {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			p.setOriginHeaders(w, origin)
+			w.Header().Set(headerAllowMethods, p.methods)
+			if len(p.allowedHeaders) > 0 {
+				w.Header().Set(headerAllowHeaders, p.allowedHeaders)
+			}
+			if len(p.maxAge) > 0 {
+				w.Header().Set(headerMaxAge, p.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// Actual request
+		if p.isOriginAllowed(origin) && p.allowedMethods[req.Method] {
+			p.setOriginHeaders(w, origin)
+		}
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,184 @@
+package webserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/models"
+	"github.com/bitcoin-sv/alert-system/app/p2p"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://alerts.example.com"
+
+// testCORSConfig is a policy used for testing
+func testCORSConfig() config.CORSConfig {
+	return config.CORSConfig{
+		AllowedHeaders: config.DefaultCORSAllowedHeaders,
+		AllowedMethods: config.DefaultCORSAllowedMethods,
+		AllowedOrigins: []string{testOrigin},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// newPreflightRequest will create a new preflight request
+func newPreflightRequest(path, origin, method string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set(headerOrigin, origin)
+	req.Header.Set(headerRequestMethod, method)
+	return req
+}
+
+// TestCORSPolicy_Handler will test the method Handler()
+func TestCORSPolicy_Handler(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("default policy is same-origin", func(t *testing.T) {
+		h := newCORSPolicy(config.CORSConfig{
+			AllowedHeaders: config.DefaultCORSAllowedHeaders,
+			AllowedMethods: config.DefaultCORSAllowedMethods,
+		}).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", testOrigin, http.MethodGet))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(headerOrigin, testOrigin)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+		assert.Empty(t, w.Header().Get(headerAllowCredentials))
+	})
+
+	t.Run("no origin is passed through", func(t *testing.T) {
+		h := newCORSPolicy(testCORSConfig()).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+		assert.Empty(t, w.Header().Get(headerVary))
+	})
+
+	t.Run("allowed origin preflight", func(t *testing.T) {
+		h := newCORSPolicy(testCORSConfig()).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", testOrigin, http.MethodGet))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, testOrigin, w.Header().Get(headerAllowOrigin))
+		assert.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get(headerAllowMethods))
+		assert.Equal(t, "Content-Type", w.Header().Get(headerAllowHeaders))
+		assert.Equal(t, "600", w.Header().Get(headerMaxAge))
+		assert.Equal(t, headerOrigin, w.Header().Get(headerVary))
+		assert.Empty(t, w.Header().Get(headerAllowCredentials))
+	})
+
+	t.Run("disallowed method preflight", func(t *testing.T) {
+		h := newCORSPolicy(testCORSConfig()).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", testOrigin, http.MethodDelete))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+	})
+
+	t.Run("disallowed origin preflight", func(t *testing.T) {
+		h := newCORSPolicy(testCORSConfig()).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", "https://evil.example.com", http.MethodGet))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+	})
+
+	t.Run("wildcard without credentials", func(t *testing.T) {
+		conf := testCORSConfig()
+		conf.AllowedOrigins = []string{wildcard}
+		h := newCORSPolicy(conf).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", testOrigin, http.MethodGet))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, wildcard, w.Header().Get(headerAllowOrigin))
+	})
+
+	t.Run("wildcard with credentials echoes the origin", func(t *testing.T) {
+		conf := testCORSConfig()
+		conf.AllowedOrigins = []string{wildcard}
+		conf.AllowCredentials = true
+		h := newCORSPolicy(conf).Handler(next)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newPreflightRequest("/", testOrigin, http.MethodGet))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, testOrigin, w.Header().Get(headerAllowOrigin))
+		assert.Equal(t, "true", w.Header().Get(headerAllowCredentials))
+	})
+}
+
+// TestServer_Handlers_CORS will test the preflight behavior of the registered routes
+func TestServer_Handlers_CORS(t *testing.T) {
+
+	// Set the env to test
+	err := os.Setenv(config.EnvironmentKey, config.EnvironmentTest)
+	require.NoError(t, err)
+
+	// Load the config from env/json
+	var dependencies *config.Config
+	dependencies, err = config.LoadDependencies(context.Background(), models.BaseModels, true)
+	require.NoError(t, err)
+	require.NotNil(t, dependencies)
+	defer dependencies.CloseAll(context.Background())
+
+	for _, path := range []string{"/", "/alerts", "/alert/1"} {
+		t.Run("default policy rejects preflight on "+path, func(t *testing.T) {
+			s := NewServer(dependencies, &p2p.Server{})
+			w := httptest.NewRecorder()
+			s.Handlers().ServeHTTP(w, newPreflightRequest(path, testOrigin, http.MethodGet))
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Empty(t, w.Header().Get(headerAllowOrigin))
+		})
+	}
+
+	t.Run("plain options request on /", func(t *testing.T) {
+		s := NewServer(dependencies, &p2p.Server{})
+		w := httptest.NewRecorder()
+		s.Handlers().ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(headerAllowOrigin))
+	})
+
+	dependencies.WebServer.CORS.AllowedOrigins = []string{testOrigin}
+	for _, path := range []string{"/", "/alerts", "/alert/1"} {
+		t.Run("allowed origin preflight on "+path, func(t *testing.T) {
+			s := NewServer(dependencies, &p2p.Server{})
+			w := httptest.NewRecorder()
+			s.Handlers().ServeHTTP(w, newPreflightRequest(path, testOrigin, http.MethodGet))
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, testOrigin, w.Header().Get(headerAllowOrigin))
+			assert.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get(headerAllowMethods))
+		})
+
+		t.Run("disallowed method preflight on "+path, func(t *testing.T) {
+			s := NewServer(dependencies, &p2p.Server{})
+			w := httptest.NewRecorder()
+			s.Handlers().ServeHTTP(w, newPreflightRequest(path, testOrigin, http.MethodPost))
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Empty(t, w.Header().Get(headerAllowOrigin))
+		})
+	}
+}
@@ -6,13 +6,11 @@ import (
 	"crypto/tls"
 	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/bitcoin-sv/alert-system/app/api/base"
 	"github.com/bitcoin-sv/alert-system/app/config"
 	p2palert "github.com/bitcoin-sv/alert-system/app/p2p"
 	apirouter "github.com/mrz1836/go-api-router"
-	"github.com/newrelic/go-agent/v3/integrations/nrhttprouter"
 )
 
 const (
@@ -82,7 +80,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 }
 
 // Handlers will return handlers
-func (s *Server) Handlers() *nrhttprouter.Router {
+func (s *Server) Handlers() http.Handler {
 
 	// Create a new router
 	s.Router = apirouter.New()
@@ -90,23 +88,12 @@ func (s *Server) Handlers() *nrhttprouter.Router {
 	// Custom logger
 	s.Router.Logger = s.Config.Services.Log
 
-	// Turned on all CORs for now
-	s.Router.CrossOriginEnabled = true
-	s.Router.CrossOriginAllowCredentials = true
-	s.Router.CrossOriginAllowOrigin = wildcard
-	s.Router.CrossOriginAllowHeaders = wildcard
-	s.Router.CrossOriginAllowMethods = strings.Join([]string{
-		http.MethodDelete,
-		http.MethodGet,
-		http.MethodOptions,
-		http.MethodPatch,
-		http.MethodPost,
-		http.MethodPut,
-	}, ",")
+	// CORS is applied by our own policy (see cors.go) instead of the router
+	s.Router.CrossOriginEnabled = false
 
 	// Register all actions (routes / handlers)
 	base.RegisterRoutes(s.Router, s.Config, s.P2pServer)
 
-	// Return the router
-	return s.Router.HTTPRouter
+	// Return the router wrapped in the CORS policy
+	return newCORSPolicy(s.Config.WebServer.CORS).Handler(s.Router.HTTPRouter)
 }
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/history"
+	"github.com/bitcoin-sv/alert-system/app/migrations"
+)
+
+// command is a maintenance command that runs instead of the server
+type command struct {
+	run   func(ctx context.Context, conf *config.Config, args []string) error
+	usage string
+}
+
+// Command usage
+const (
+	configUsage  = "config <check|dump>: check the configuration and print all the problems, or print the effective configuration"
+	exportUsage  = "export <file>: export the alert history"
+	importUsage  = "import <file>: import and verify an alert history export"
+	migrateUsage = "migrate <status|up>: show or apply the datastore migrations"
+	verifyUsage  = "verify: replay the saved alert history from genesis and check every alert"
+)
+
+// commands are the available maintenance commands by name
+var commands = map[string]command{
+	"config":  {run: configCommand, usage: configUsage},
+	"export":  {run: exportCommand, usage: exportUsage},
+	"import":  {run: importCommand, usage: importUsage},
+	"migrate": {run: migrateCommand, usage: migrateUsage},
+	"verify":  {run: verifyCommand, usage: verifyUsage},
+}
+
+// runCommand will run the command given on the command line
+func runCommand(ctx context.Context, conf *config.Config, args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command [%s], commands:\n%s", args[0], commandUsage())
+	}
+	return cmd.run(ctx, conf, args[1:])
+}
+
+// runCommandOrExit will run the command given on the command line and exit on error
+func runCommandOrExit(ctx context.Context, conf *config.Config, args []string) {
+	if err := runCommand(ctx, conf, args); err != nil {
+		conf.Services.Log.Errorf("error running command: %s", err.Error())
+		conf.CloseAll(ctx)
+		os.Exit(1)
+	}
+}
+
+// commandUsage returns the usage of all commands
+func commandUsage() string {
+	lines := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		lines = append(lines, "  "+cmd.usage)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// configCommand will check or print the configuration, this runs before the configuration is loaded (conf is nil)
+func configCommand(_ context.Context, _ *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + configUsage)
+	}
+
+	switch args[0] {
+	case "check":
+		err := config.CheckConfigFile()
+		var problems config.ValidationErrors
+		if errors.As(err, &problems) {
+			fmt.Printf("the configuration has %d problems:\n", len(problems))
+			for _, problem := range problems {
+				fmt.Println("  " + problem.Error())
+			}
+			return errors.New("the configuration is not valid")
+		} else if err != nil {
+			return err
+		}
+		fmt.Println("the configuration is valid")
+		return nil
+	case "dump":
+		effective, err := config.LoadEffectiveConfig()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("config file: %s\n", effective.ConfigFile)
+		fmt.Printf("environment: %s\n", effective.Environment)
+		for _, setting := range effective.Settings {
+			value, _ := json.Marshal(setting.Value)
+			fmt.Printf("  %s = %s (%s)\n", setting.Name, value, setting.Source)
+		}
+		return nil
+	default:
+		return errors.New("usage: " + configUsage)
+	}
+}
+
+// exportCommand will export the alert history to a file
+func exportCommand(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + exportUsage)
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if count, err = history.Export(ctx, conf, f); err != nil {
+		_ = f.Close()
+		return err
+	} else if err = f.Close(); err != nil {
+		return err
+	}
+	conf.Services.Log.Infof("exported %d alerts to %s", count, args[0])
+	return nil
+}
+
+// importCommand will import the alert history from a file
+func importCommand(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + importUsage)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	result, err := history.Import(ctx, conf, f)
+	if result != nil {
+		conf.Services.Log.Infof(
+			"imported %d alerts, skipped %d already saved, latest sequence is %d",
+			result.Imported, result.Skipped, result.LatestSequence,
+		)
+	}
+	return err
+}
+
+// verifyCommand will verify the saved alert history
+func verifyCommand(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: " + verifyUsage)
+	}
+
+	result, err := history.Verify(ctx, conf)
+	if err != nil {
+		return err
+	} else if result.Inconsistency != nil {
+		return fmt.Errorf("alert history is not valid after %d alerts: %w", result.Alerts, result.Inconsistency)
+	}
+	conf.Services.Log.Infof("verified %d alerts, the alert history is valid up to sequence %d", result.Alerts, result.LatestSequence)
+	return nil
+}
+
+// migrateCommand will show or apply the datastore migrations
+func migrateCommand(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: " + migrateUsage)
+	}
+
+	switch args[0] {
+	case "status":
+		status, err := migrations.GetStatus(ctx, conf)
+		if err != nil {
+			return err
+		}
+		for _, migration := range status.Applied {
+			conf.Services.Log.Infof("applied migration %d: %s (%s)",
+				migration.Version, migration.Description, migration.CreatedAt.Format(time.RFC3339))
+		}
+		for _, migration := range status.Pending {
+			conf.Services.Log.Infof("pending migration %d: %s", migration.Version, migration.Description)
+		}
+		conf.Services.Log.Infof("datastore version %d, latest version %d", status.Current, status.Latest)
+		if status.Current > status.Latest {
+			return migrations.ErrSchemaNewer
+		}
+		return nil
+	case "up":
+		applied, err := migrations.Up(ctx, conf)
+		if err != nil {
+			return err
+		}
+		conf.Services.Log.Infof("applied %d migrations, datastore version %d", len(applied), migrations.Latest())
+		return nil
+	default:
+		return errors.New("usage: " + migrateUsage)
+	}
+}
@@ -8,15 +8,26 @@ import (
 	"os/signal"
 
 	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/grpcserver"
+	"github.com/bitcoin-sv/alert-system/app/migrations"
 	"github.com/bitcoin-sv/alert-system/app/models"
 	"github.com/bitcoin-sv/alert-system/app/models/model"
 	"github.com/bitcoin-sv/alert-system/app/p2p"
+	"github.com/bitcoin-sv/alert-system/app/webhook"
 	"github.com/bitcoin-sv/alert-system/app/webserver"
 )
 
 // main is the entry point for the alert-system
 func main() {
 
+	// Check the configuration instead of loading it (prints all the problems at once)
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runCommand(context.Background(), nil, os.Args[1:]); err != nil {
+			log.Fatalf("error running command: %s", err.Error())
+		}
+		return
+	}
+
 	// Load the configuration and services
 	_appConfig, err := config.LoadDependencies(context.Background(), models.BaseModels, false)
 	if err != nil {
@@ -26,6 +37,28 @@ func main() {
 		_appConfig.CloseAll(context.Background())
 	}()
 
+	// Ensure the webhook endpoints are valid (alert type filters and payload templates)
+	if _, err = webhook.NewEndpoints(_appConfig); err != nil {
+		_appConfig.Services.Log.Fatalf("error loading webhook endpoints: %s", err.Error())
+	}
+
+	// Show or apply the migrations instead of the server (before the datastore version is checked)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runCommandOrExit(context.Background(), _appConfig, os.Args[1:])
+		return
+	}
+
+	// Apply the pending migrations (or only check them if auto migrate is disabled), this
+	// refuses to start on a datastore that is newer than this binary
+	if _appConfig.Datastore.AutoMigrate {
+		_, err = migrations.Up(context.Background(), _appConfig)
+	} else {
+		err = migrations.Check(context.Background(), _appConfig)
+	}
+	if err != nil {
+		_appConfig.Services.Log.Fatalf("error migrating datastore: %s", err.Error())
+	}
+
 	// Ensure we have the genesis alert in the database
 	if err = models.CreateGenesisAlert(
 		context.Background(), model.WithAllDependencies(_appConfig),
@@ -33,6 +66,12 @@ func main() {
 		_appConfig.Services.Log.Fatalf("error creating genesis alert: %s", err.Error())
 	}
 
+	// Run a maintenance command instead of the server (e.g. alert-system export history.jsonl)
+	if len(os.Args) > 1 {
+		runCommandOrExit(context.Background(), _appConfig, os.Args[1:])
+		return
+	}
+
 	// Ensure that RPC connection is valid
 	if !_appConfig.DisableRPCVerification {
 		if _, err = _appConfig.Services.Node.BestBlockHash(context.Background()); err != nil {
@@ -53,11 +92,21 @@ func main() {
 	// Create a new (web) server
 	webServer := webserver.NewServer(_appConfig, p2pServer)
 
+	// Create the gRPC server (if enabled)
+	var grpcServer *grpcserver.Server
+	if _appConfig.GRPC.Enabled {
+		grpcServer = grpcserver.NewServer(_appConfig, p2pServer)
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	// Start the p2p server
 	if err = p2pServer.Start(ctx); err != nil {
 		_appConfig.Services.Log.Fatalf("error starting p2p server: %s", err.Error())
 	}
+
+	// Reload the configuration on SIGHUP (or when the config file changes)
+	go watchConfig(ctx, _appConfig)
+
 	// Sync a channel to listen for interrupts
 	idleConnectionsClosed := make(chan struct{})
 	go func(appConfig *config.Config) {
@@ -78,6 +127,13 @@ func main() {
 			appConfig.Services.Log.Infof("error shutting down webserver: %s", err.Error())
 		}
 
+		// Shutdown the gRPC server
+		if grpcServer != nil {
+			if err = grpcServer.Shutdown(ctxTimeout); err != nil {
+				appConfig.Services.Log.Infof("error shutting down gRPC server: %s", err.Error())
+			}
+		}
+
 		// Shutdown the p2p server
 		if err = p2pServer.Stop(ctxTimeout); err != nil {
 			appConfig.Services.Log.Infof("error shutting down p2p server: %s", err.Error())
@@ -90,6 +146,11 @@ func main() {
 		}
 	}(_appConfig)
 
+	// Serve the gRPC server in the background
+	if grpcServer != nil {
+		go grpcServer.Serve()
+	}
+
 	// Serve the web server and then wait endlessly
 	webServer.Serve()
 
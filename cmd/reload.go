@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bitcoin-sv/alert-system/app/config"
+	"github.com/bitcoin-sv/alert-system/app/webhook"
+)
+
+// watchConfig will reload the configuration on SIGHUP, and when the custom config file changes
+// (if config_watch_interval is set), until the context is done
+func watchConfig(ctx context.Context, appConfig *config.Config) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	// Check the custom config file for changes (the embedded environment files cannot change)
+	var fileChanged <-chan time.Time
+	configFile := os.Getenv(config.EnvironmentCustomFilePath)
+	modified := modifiedTime(configFile)
+	if appConfig.ConfigWatchInterval > 0 && len(configFile) > 0 {
+		ticker := time.NewTicker(appConfig.ConfigWatchInterval)
+		defer ticker.Stop()
+		fileChanged = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			appConfig.Services.Log.Infof("hangup signal received, reloading configuration")
+		case <-fileChanged:
+			m := modifiedTime(configFile)
+			if m.Equal(modified) {
+				continue
+			}
+			modified = m
+			appConfig.Services.Log.Infof("config file %s changed, reloading configuration", configFile)
+		}
+		reloadConfig(appConfig)
+	}
+}
+
+// reloadConfig will reload the configuration and log what changed
+func reloadConfig(appConfig *config.Config) {
+	result, err := appConfig.Reload(func(newConfig *config.Config) error {
+		_, endpointsErr := webhook.NewEndpoints(newConfig)
+		return endpointsErr
+	})
+	if err != nil {
+		appConfig.Services.Log.Errorf("error reloading configuration (nothing was changed): %s", err.Error())
+		return
+	}
+
+	if len(result.Applied) > 0 {
+		appConfig.Services.Log.Infof("applied configuration changes: %s", strings.Join(result.Applied, ", "))
+	}
+	if len(result.RequiresRestart) > 0 {
+		appConfig.Services.Log.Warnf("configuration changes that require a restart (not applied): %s", strings.Join(result.RequiresRestart, ", "))
+	}
+	if len(result.Applied) == 0 && len(result.RequiresRestart) == 0 {
+		appConfig.Services.Log.Infof("configuration has not changed")
+	}
+}
+
+// modifiedTime returns the modification time of the file (zero if it cannot be read)
+func modifiedTime(file string) time.Time {
+	if len(file) == 0 {
+		return time.Time{}
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -6,4 +6,5 @@ const (
 	FieldDeletedAt      = "deleted_at"      // Deleted at timestamp on every model
 	FieldID             = "id"              // ID is a generic id for many models
 	FieldSequenceNumber = "sequence_number" // SequenceNumber is used for the alert message sequencing
+	FieldStatus         = "status"          // Status is used for models with a lifecycle (webhook deliveries)
 )
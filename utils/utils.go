@@ -12,6 +12,9 @@ const (
 	// GreaterThanCondition is the greater than condition for database queries
 	GreaterThanCondition = "$gt"
 
+	// LessThanCondition is the less than condition for database queries
+	LessThanCondition = "$lt"
+
 	// LessThanOrEqualCondition is the less than or equal condition for database queries
 	LessThanOrEqualCondition = "$lte"
 